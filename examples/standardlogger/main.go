@@ -121,9 +121,18 @@ func main() {
 			Del("remove", "and")
 		_ = routineLabels2
 
-		routineLog := standardlogger.New(routineLabels)
+		// ==========================
+		// CHILD LOGGERS
+		// ==========================
+		// WithLabels creates a child logger that shares outputs
+		// with the parent and adds labels to the parent's labels.
+		routineLog := log.WithLabels(routineLabels)
 		defer routineLog.PanicLogger()
 
+		// With creates a child logger that adds fields to every entry.
+		requestLog := routineLog.With(logger.F{"reqId": 22})
+		requestLog.Info("Info from request")
+
 		// logging in goroutine
 		routineLog.Info("Info from goroutine")
 
@@ -2,10 +2,18 @@ module github.com/dataphos/lib-logger
 
 go 1.17
 
-require go.uber.org/zap v1.23.0
+require (
+	github.com/pkg/errors v0.9.1
+	go.opentelemetry.io/otel/sdk v1.10.0
+	go.opentelemetry.io/otel/trace v1.10.0
+	go.uber.org/multierr v1.6.0
+	go.uber.org/zap v1.23.0
+)
 
 require (
-	github.com/pkg/errors v0.9.1 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel v1.10.0 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
-	go.uber.org/multierr v1.6.0 // indirect
+	golang.org/x/sys v0.0.0-20210423185535-09eb48e85fd7 // indirect
 )
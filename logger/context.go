@@ -0,0 +1,31 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries log.
+func NewContext(ctx context.Context, log Log) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns the Log carried by ctx, if any.
+func FromContext(ctx context.Context) (Log, bool) {
+	log, ok := ctx.Value(contextKey{}).(Log)
+
+	return log, ok
+}
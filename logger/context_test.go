@@ -0,0 +1,47 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+type contextLog struct {
+	logger.Log
+	name string
+}
+
+func TestNewContext(t *testing.T) {
+	log := &contextLog{name: "log"}
+	ctx := logger.NewContext(context.Background(), log)
+
+	fromCtx, ok := logger.FromContext(ctx)
+	if !ok {
+		t.Fatal("Log not found in context.")
+	}
+
+	if fromCtx != log {
+		t.Error("Wrong log found in context.")
+	}
+}
+
+func TestFromContext_Missing(t *testing.T) {
+	if _, ok := logger.FromContext(context.Background()); ok {
+		t.Error("Log found in empty context.")
+	}
+}
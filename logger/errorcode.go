@@ -0,0 +1,189 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrDuplicateErrorCode is returned when registering an error code that is already registered.
+var ErrDuplicateErrorCode = errors.New("duplicate error code")
+
+// ErrorCode describes an error code passed to Error, Fatal and Panic.
+type ErrorCode struct {
+	Code uint64 `json:"code"`
+	// Name is a short identifier of the error, such as "BrokerUnreachable".
+	Name string `json:"name"`
+	// Severity is the level Error entries with the code are logged at, if it is lower than LevelError,
+	// e.g. Severity(LevelWarn) for errors that are expected and recovered from. Nil keeps LevelError.
+	Severity *Level `json:"severity,omitempty"`
+	// Category groups related errors, such as "connectivity" or "validation".
+	Category string `json:"category,omitempty"`
+	// Remediation tells the operator what to do about the error.
+	Remediation string `json:"remediation,omitempty"`
+	DocsURL     string `json:"docs_url,omitempty"`
+}
+
+// Severity returns lvl as an ErrorCode.Severity.
+func Severity(lvl Level) *Level {
+	return &lvl
+}
+
+// ErrorCodeRegistry holds the error codes of an application. It is safe for concurrent use.
+type ErrorCodeRegistry struct {
+	mu         sync.RWMutex
+	codes      map[uint64]ErrorCode
+	duplicates []ErrorCode
+}
+
+// DefaultErrorCodeRegistry is the registry used by RegisterErrorCodes and LookupErrorCode.
+var DefaultErrorCodeRegistry = NewErrorCodeRegistry()
+
+// NewErrorCodeRegistry returns an empty registry.
+func NewErrorCodeRegistry() *ErrorCodeRegistry {
+	return &ErrorCodeRegistry{codes: map[uint64]ErrorCode{}}
+}
+
+// RegisterErrorCodes registers codes in DefaultErrorCodeRegistry.
+func RegisterErrorCodes(codes ...ErrorCode) error {
+	return DefaultErrorCodeRegistry.Register(codes...)
+}
+
+// LookupErrorCode returns the code registered in DefaultErrorCodeRegistry.
+func LookupErrorCode(code uint64) (ErrorCode, bool) {
+	return DefaultErrorCodeRegistry.Lookup(code)
+}
+
+// Register registers codes. Codes that are already registered keep their first registration
+// and are reported by the returned error, which wraps ErrDuplicateErrorCode.
+func (r *ErrorCodeRegistry) Register(codes ...ErrorCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var duplicates []string
+
+	for _, code := range codes {
+		if registered, ok := r.codes[code.Code]; ok {
+			duplicates = append(duplicates, fmt.Sprintf("%d (%s, registered as %s)", code.Code, code.Name, registered.Name))
+			r.duplicates = append(r.duplicates, code)
+
+			continue
+		}
+
+		r.codes[code.Code] = code
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateErrorCode, strings.Join(duplicates, ", "))
+	}
+
+	return nil
+}
+
+// Lookup returns the registered code.
+func (r *ErrorCodeRegistry) Lookup(code uint64) (ErrorCode, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	errorCode, ok := r.codes[code]
+
+	return errorCode, ok
+}
+
+// Duplicates returns the codes that were rejected by Register because they were already registered,
+// in the order they were registered.
+func (r *ErrorCodeRegistry) Duplicates() []ErrorCode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]ErrorCode(nil), r.duplicates...)
+}
+
+// Codes returns all registered codes, ordered by code.
+func (r *ErrorCodeRegistry) Codes() []ErrorCode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codes := make([]ErrorCode, 0, len(r.codes))
+	for _, code := range r.codes {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].Code < codes[j].Code
+	})
+
+	return codes
+}
+
+// WriteJSON writes the registered codes as a JSON array, ordered by code.
+func (r *ErrorCodeRegistry) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(r.Codes()); err != nil {
+		return fmt.Errorf("writing error codes: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMarkdown writes the registered codes as a Markdown table, ordered by code, e.g. for a product's documentation.
+func (r *ErrorCodeRegistry) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("| Code | Name | Severity | Category | Remediation | Docs |\n")
+	b.WriteString("|------|------|----------|----------|-------------|------|\n")
+
+	for _, code := range r.Codes() {
+		docs := ""
+		if code.DocsURL != "" {
+			docs = "[docs](" + code.DocsURL + ")"
+		}
+
+		severity := ""
+		if code.Severity != nil {
+			severity = code.Severity.String()
+		}
+
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n",
+			code.Code,
+			markdownCell(code.Name),
+			severity,
+			markdownCell(code.Category),
+			markdownCell(code.Remediation),
+			docs,
+		)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing error codes: %w", err)
+	}
+
+	return nil
+}
+
+// markdownCell escapes pipes and line breaks, which would break the table.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+
+	return strings.Join(strings.Fields(text), " ")
+}
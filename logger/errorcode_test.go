@@ -0,0 +1,160 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+var (
+	brokerUnreachable = logger.ErrorCode{
+		Code:        1001,
+		Name:        "BrokerUnreachable",
+		Severity:    logger.Severity(logger.LevelError),
+		Category:    "connectivity",
+		Remediation: "Check the broker address | credentials.",
+		DocsURL:     "https://docs.dataphos.com/errors/1001",
+	}
+	invalidMessage = logger.ErrorCode{
+		Code:     1000,
+		Name:     "InvalidMessage",
+		Severity: logger.Severity(logger.LevelWarn),
+		Category: "validation",
+	}
+)
+
+func TestErrorCodeRegistry_Register(t *testing.T) {
+	registry := logger.NewErrorCodeRegistry()
+
+	if err := registry.Register(brokerUnreachable, invalidMessage); err != nil {
+		t.Fatalf("Register failed: %v.", err)
+	}
+
+	code, ok := registry.Lookup(1001)
+	if !ok || code != brokerUnreachable {
+		t.Errorf("Wrong code %+v.", code)
+	}
+
+	if _, ok := registry.Lookup(1002); ok {
+		t.Error("Unregistered code found.")
+	}
+
+	codes := registry.Codes()
+	if len(codes) != 2 || codes[0].Code != 1000 || codes[1].Code != 1001 {
+		t.Errorf("Wrong codes %+v.", codes)
+	}
+}
+
+func TestErrorCodeRegistry_RegisterDuplicate(t *testing.T) {
+	registry := logger.NewErrorCodeRegistry()
+	registry.Register(brokerUnreachable)
+
+	duplicate := brokerUnreachable
+	duplicate.Name = "BrokerDown"
+
+	err := registry.Register(duplicate, invalidMessage)
+	if !errors.Is(err, logger.ErrDuplicateErrorCode) {
+		t.Fatalf("Expected ErrDuplicateErrorCode, got %v.", err)
+	}
+
+	if !strings.Contains(err.Error(), "BrokerDown") || !strings.Contains(err.Error(), "BrokerUnreachable") {
+		t.Errorf("Error doesn't name both codes: %v.", err)
+	}
+
+	if code, _ := registry.Lookup(1001); code.Name != "BrokerUnreachable" {
+		t.Errorf("First registration replaced by %s.", code.Name)
+	}
+
+	if _, ok := registry.Lookup(1000); !ok {
+		t.Error("Codes after the duplicate weren't registered.")
+	}
+
+	if duplicates := registry.Duplicates(); len(duplicates) != 1 || duplicates[0].Name != "BrokerDown" {
+		t.Errorf("Wrong duplicates %v.", duplicates)
+	}
+}
+
+func TestErrorCodeRegistry_WriteJSON(t *testing.T) {
+	registry := logger.NewErrorCodeRegistry()
+	registry.Register(brokerUnreachable, invalidMessage)
+
+	var buf bytes.Buffer
+	if err := registry.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v.", err)
+	}
+
+	var codes []logger.ErrorCode
+	if err := json.Unmarshal(buf.Bytes(), &codes); err != nil {
+		t.Fatalf("Invalid JSON %s: %v.", buf.String(), err)
+	}
+
+	if !reflect.DeepEqual(codes, []logger.ErrorCode{invalidMessage, brokerUnreachable}) {
+		t.Errorf("Wrong codes %+v.", codes)
+	}
+
+	if !strings.Contains(buf.String(), `"severity": "warn"`) {
+		t.Errorf("Severity isn't written by name: %s", buf.String())
+	}
+}
+
+func TestErrorCodeRegistry_WriteMarkdown(t *testing.T) {
+	registry := logger.NewErrorCodeRegistry()
+	registry.Register(brokerUnreachable, invalidMessage)
+
+	var buf bytes.Buffer
+	if err := registry.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v.", err)
+	}
+
+	expected := "| Code | Name | Severity | Category | Remediation | Docs |\n" +
+		"|------|------|----------|----------|-------------|------|\n" +
+		"| 1000 | InvalidMessage | warn | validation |  |  |\n" +
+		"| 1001 | BrokerUnreachable | error | connectivity | Check the broker address \\| credentials. | [docs](https://docs.dataphos.com/errors/1001) |\n"
+
+	if buf.String() != expected {
+		t.Errorf("Wrong table:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestErrorCodeRegistry_WithoutSeverity(t *testing.T) {
+	registry := logger.NewErrorCodeRegistry()
+	registry.Register(logger.ErrorCode{Code: 1002, Name: "Unclassified"})
+
+	var buf bytes.Buffer
+	if err := registry.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v.", err)
+	}
+
+	if !strings.HasSuffix(buf.String(), "| 1002 | Unclassified |  |  |  |  |\n") {
+		t.Errorf("Wrong table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := registry.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v.", err)
+	}
+
+	if strings.Contains(buf.String(), "severity") {
+		t.Errorf("Severity written for a code without one: %s", buf.String())
+	}
+}
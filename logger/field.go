@@ -0,0 +1,142 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"math"
+	"time"
+)
+
+// FieldType tells how a Field's value is stored.
+type FieldType uint8
+
+const (
+	FieldTypeString FieldType = iota + 1
+	FieldTypeInt
+	FieldTypeUint
+	FieldTypeFloat
+	FieldTypeBool
+	FieldTypeDuration
+	FieldTypeTime
+	FieldTypeError
+	FieldTypeObject
+	FieldTypeArray
+	FieldTypeAny
+)
+
+// Field is a typed key-value pair. Unlike Fields, a list of Field keeps its order
+// and is converted to the underlying logger's fields without reflection.
+// Fields should be created with the constructors, such as String or Int.
+type Field struct {
+	Key  string
+	Type FieldType
+	// Integer holds integers, floats as their IEEE 754 bits, booleans as 0 or 1 and durations.
+	Integer int64
+	String  string
+	// Interface holds times, errors, nested fields of objects and arrays and values of Any.
+	Interface interface{}
+}
+
+// String returns a string field.
+func String(key, val string) Field {
+	return Field{Key: key, Type: FieldTypeString, String: val}
+}
+
+// Int returns an integer field.
+func Int(key string, val int) Field {
+	return Int64(key, int64(val))
+}
+
+// Int64 returns an integer field.
+func Int64(key string, val int64) Field {
+	return Field{Key: key, Type: FieldTypeInt, Integer: val}
+}
+
+// Uint64 returns an unsigned integer field.
+func Uint64(key string, val uint64) Field {
+	return Field{Key: key, Type: FieldTypeUint, Integer: int64(val)}
+}
+
+// Float64 returns a floating point field.
+func Float64(key string, val float64) Field {
+	return Field{Key: key, Type: FieldTypeFloat, Integer: int64(math.Float64bits(val))}
+}
+
+// Bool returns a boolean field.
+func Bool(key string, val bool) Field {
+	var integer int64
+	if val {
+		integer = 1
+	}
+
+	return Field{Key: key, Type: FieldTypeBool, Integer: integer}
+}
+
+// Duration returns a duration field.
+func Duration(key string, val time.Duration) Field {
+	return Field{Key: key, Type: FieldTypeDuration, Integer: int64(val)}
+}
+
+// Time returns a time field.
+func Time(key string, val time.Time) Field {
+	return Field{Key: key, Type: FieldTypeTime, Interface: val}
+}
+
+// Err returns a field with the key "error" that describes err. A nil err is skipped.
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+// NamedErr returns a field that describes err. A nil err is skipped.
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Type: FieldTypeError, Interface: err}
+}
+
+// Object returns a field holding a nested object made of fields, in the given order.
+func Object(key string, fields ...Field) Field {
+	return Field{Key: key, Type: FieldTypeObject, Interface: fields}
+}
+
+// Array returns a field holding an array of the values of elems. Keys of elems are ignored:
+//
+//	logger.Array("users", logger.Object("", logger.String("name", "Ann")), logger.Object("", logger.String("name", "Bob")))
+func Array(key string, elems ...Field) Field {
+	return Field{Key: key, Type: FieldTypeArray, Interface: elems}
+}
+
+// Strings returns a field holding an array of strings.
+func Strings(key string, vals []string) Field {
+	elems := make([]Field, len(vals))
+	for i, val := range vals {
+		elems[i] = String("", val)
+	}
+
+	return Array(key, elems...)
+}
+
+// Ints returns a field holding an array of integers.
+func Ints(key string, vals []int) Field {
+	elems := make([]Field, len(vals))
+	for i, val := range vals {
+		elems[i] = Int("", val)
+	}
+
+	return Array(key, elems...)
+}
+
+// Any returns a field holding val of any type, converted the same way as values of Fields.
+func Any(key string, val interface{}) Field {
+	return Field{Key: key, Type: FieldTypeAny, Interface: val}
+}
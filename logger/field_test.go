@@ -0,0 +1,67 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+func TestFieldConstructors(t *testing.T) {
+	now := time.Now()
+	err := errors.New("failed")
+
+	tests := []struct {
+		field    logger.Field
+		expected logger.Field
+	}{
+		{logger.String("k", "v"), logger.Field{Key: "k", Type: logger.FieldTypeString, String: "v"}},
+		{logger.Int("k", -5), logger.Field{Key: "k", Type: logger.FieldTypeInt, Integer: -5}},
+		{logger.Uint64("k", 5), logger.Field{Key: "k", Type: logger.FieldTypeUint, Integer: 5}},
+		{logger.Float64("k", 1.5), logger.Field{Key: "k", Type: logger.FieldTypeFloat, Integer: int64(math.Float64bits(1.5))}},
+		{logger.Bool("k", true), logger.Field{Key: "k", Type: logger.FieldTypeBool, Integer: 1}},
+		{logger.Bool("k", false), logger.Field{Key: "k", Type: logger.FieldTypeBool}},
+		{logger.Duration("k", time.Second), logger.Field{Key: "k", Type: logger.FieldTypeDuration, Integer: int64(time.Second)}},
+		{logger.Time("k", now), logger.Field{Key: "k", Type: logger.FieldTypeTime, Interface: now}},
+		{logger.Err(err), logger.Field{Key: "error", Type: logger.FieldTypeError, Interface: err}},
+		{logger.Any("k", 5), logger.Field{Key: "k", Type: logger.FieldTypeAny, Interface: 5}},
+	}
+
+	for _, test := range tests {
+		if test.field != test.expected {
+			t.Errorf("Wrong field %+v, want %+v.", test.field, test.expected)
+		}
+	}
+}
+
+func TestFieldConstructors_Nested(t *testing.T) {
+	object := logger.Object("user", logger.String("name", "Ann"), logger.Int("age", 30))
+
+	fields, ok := object.Interface.([]logger.Field)
+	if object.Type != logger.FieldTypeObject || !ok || len(fields) != 2 || fields[0].Key != "name" {
+		t.Errorf("Wrong object %+v.", object)
+	}
+
+	array := logger.Strings("ids", []string{"a", "b"})
+
+	elems, ok := array.Interface.([]logger.Field)
+	if array.Type != logger.FieldTypeArray || !ok || len(elems) != 2 || elems[1].String != "b" {
+		t.Errorf("Wrong array %+v.", array)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrInvalidLabelKey is returned for empty label keys and keys with characters outside of the allowed set.
+	ErrInvalidLabelKey = errors.New("invalid label key")
+	// ErrReservedLabelKey is returned for label keys that collide with keys written by the logger.
+	ErrReservedLabelKey = errors.New("reserved label key")
+	// ErrLabelTooLong is returned for label keys and values longer than the policy allows.
+	ErrLabelTooLong = errors.New("label too long")
+	// ErrDuplicateLabelKey is returned when different keys end up as the same key after normalization.
+	ErrDuplicateLabelKey = errors.New("duplicate label key")
+)
+
+// ReservedKeys are the keys written by the logger itself. Labels and fields with these keys would produce
+// duplicate keys in JSON entries. Implementations may reserve more keys, e.g. those of their output format.
+var ReservedKeys = []string{"ts", "level", "msg", "caller", "stacktrace", "code", "tags"}
+
+// LabelMode selects what a LabelPolicy does with labels that break it.
+type LabelMode int
+
+const (
+	// LabelModeStrict drops labels that break the policy and reports them as errors.
+	LabelModeStrict LabelMode = iota + 1
+	// LabelModeLenient fixes labels that break the policy: invalid characters are replaced with underscores,
+	// long keys and values are truncated and reserved keys are renamed with the policy's prefix.
+	LabelModeLenient
+)
+
+// LabelNormalization rewrites label keys to the naming rules of a backend before they are validated.
+type LabelNormalization int
+
+const (
+	// NormalizationNone keeps keys as they are.
+	NormalizationNone LabelNormalization = iota + 1
+	// NormalizationPrometheus rewrites keys to Prometheus label names, i.e. [a-zA-Z_][a-zA-Z0-9_]*.
+	NormalizationPrometheus
+	// NormalizationKubernetes rewrites keys to Kubernetes label names, i.e. at most 63 characters of [a-zA-Z0-9._-],
+	// starting and ending with an alphanumeric character, optionally prefixed with a DNS subdomain and a slash.
+	NormalizationKubernetes
+)
+
+const (
+	// DefaultReservedPrefix is the prefix LabelModeLenient adds to reserved keys.
+	DefaultReservedPrefix = "label_"
+
+	kubernetesNameLength = 63
+)
+
+// LabelPolicy describes valid label keys and values.
+// Keys may contain ASCII letters, digits and the characters '_', '-', '.' and '/'.
+type LabelPolicy struct {
+	// Mode defaults to LabelModeStrict.
+	Mode          LabelMode
+	Normalization LabelNormalization
+	// MaxKeyLength and MaxValueLength are counted in characters. Zero means no limit.
+	MaxKeyLength   int
+	MaxValueLength int
+	// ReservedKeys are the keys labels may not use. If nil, ReservedKeys of the package are used.
+	ReservedKeys []string
+	// Prefix is added to reserved keys in LabelModeLenient. If empty, DefaultReservedPrefix is used.
+	Prefix string
+}
+
+// DefaultLabelPolicy is a lenient policy that protects ReservedKeys and limits keys to 128 and values to 1024 characters.
+var DefaultLabelPolicy = LabelPolicy{
+	Mode:           LabelModeLenient,
+	Normalization:  NormalizationNone,
+	MaxKeyLength:   128,
+	MaxValueLength: 1024,
+}
+
+// Validate returns a copy of labels that satisfies policy.
+// In LabelModeStrict, labels that break the policy are left out and reported by the returned error,
+// which wraps ErrInvalidLabelKey, ErrReservedLabelKey, ErrLabelTooLong or ErrDuplicateLabelKey.
+// In LabelModeLenient, labels are fixed instead and the error is always nil.
+// Keys are processed in sorted order, so when two keys end up the same, the first one is kept.
+func (l Labels) Validate(policy LabelPolicy) (Labels, error) {
+	valid := make(Labels, len(l))
+	sources := make(map[string]string, len(l))
+
+	var errs error
+
+	for _, key := range l.Keys() {
+		validKey, validValue, err := policy.label(key, l[key])
+		if err != nil {
+			errs = multierr.Append(errs, err)
+
+			continue
+		}
+
+		if validKey == "" {
+			continue
+		}
+
+		if source, ok := sources[validKey]; ok {
+			if policy.Mode != LabelModeLenient {
+				errs = multierr.Append(errs, fmt.Errorf("%w: %q and %q are both %q", ErrDuplicateLabelKey, source, key, validKey))
+			}
+
+			continue
+		}
+
+		sources[validKey] = key
+		valid[validKey] = validValue
+	}
+
+	return valid, errs
+}
+
+// label returns the valid key and value of a single label.
+func (p LabelPolicy) label(key, val string) (string, string, error) {
+	lenient := p.Mode == LabelModeLenient
+	validKey := p.normalize(key)
+
+	if validKey == "" {
+		// there is nothing to rename an empty key to, so it is left out in both modes.
+		if lenient {
+			return "", "", nil
+		}
+
+		return "", "", fmt.Errorf("%w: empty key", ErrInvalidLabelKey)
+	}
+
+	if !isValidLabelKey(validKey) {
+		if !lenient {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidLabelKey, key)
+		}
+
+		validKey = strings.Map(labelKeyRune, validKey)
+	}
+
+	if p.MaxKeyLength > 0 && utf8.RuneCountInString(validKey) > p.MaxKeyLength {
+		if !lenient {
+			return "", "", fmt.Errorf("%w: key %q is longer than %d characters", ErrLabelTooLong, key, p.MaxKeyLength)
+		}
+
+		validKey = truncate(validKey, p.MaxKeyLength)
+	}
+
+	if p.IsReserved(validKey) {
+		if !lenient {
+			return "", "", fmt.Errorf("%w: %q", ErrReservedLabelKey, key)
+		}
+
+		validKey = p.prefix() + validKey
+	}
+
+	if p.MaxValueLength > 0 && utf8.RuneCountInString(val) > p.MaxValueLength {
+		if !lenient {
+			return "", "", fmt.Errorf("%w: value of %q is longer than %d characters", ErrLabelTooLong, key, p.MaxValueLength)
+		}
+
+		val = truncate(val, p.MaxValueLength)
+	}
+
+	return validKey, val, nil
+}
+
+// IsReserved reports whether key is one of the policy's reserved keys.
+func (p LabelPolicy) IsReserved(key string) bool {
+	reserved := p.ReservedKeys
+	if reserved == nil {
+		reserved = ReservedKeys
+	}
+
+	for _, r := range reserved {
+		if key == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FieldKey returns key renamed with the policy's prefix if it is reserved, and key otherwise.
+// Fields are attached to single entries, so reserved field keys are renamed in both modes instead of reported.
+func (p LabelPolicy) FieldKey(key string) string {
+	if p.IsReserved(key) {
+		return p.prefix() + key
+	}
+
+	return key
+}
+
+func (p LabelPolicy) prefix() string {
+	if p.Prefix == "" {
+		return DefaultReservedPrefix
+	}
+
+	return p.Prefix
+}
+
+func (p LabelPolicy) normalize(key string) string {
+	switch p.Normalization {
+	case NormalizationPrometheus:
+		return normalizePrometheus(key)
+	case NormalizationKubernetes:
+		return normalizeKubernetes(key)
+	default:
+		return key
+	}
+}
+
+// normalizePrometheus replaces characters other than letters, digits and underscores with underscores
+// and prefixes keys starting with a digit with an underscore.
+func normalizePrometheus(key string) string {
+	if key == "" {
+		return key
+	}
+
+	key = strings.Map(func(r rune) rune {
+		if isASCIILetter(r) || isASCIIDigit(r) || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, key)
+
+	if isASCIIDigit(rune(key[0])) {
+		key = "_" + key
+	}
+
+	return key
+}
+
+// normalizeKubernetes replaces characters outside of [a-zA-Z0-9._-] with underscores, trims non-alphanumeric
+// characters from both ends and truncates the name to 63 characters. The part up to the last slash is kept as the prefix.
+func normalizeKubernetes(key string) string {
+	prefix := ""
+	if i := strings.LastIndexByte(key, '/'); i >= 0 {
+		prefix, key = strings.ToLower(key[:i+1]), key[i+1:]
+	}
+
+	key = strings.Map(func(r rune) rune {
+		if isASCIILetter(r) || isASCIIDigit(r) || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, key)
+
+	key = strings.TrimFunc(truncate(key, kubernetesNameLength), func(r rune) bool {
+		return !isASCIILetter(r) && !isASCIIDigit(r)
+	})
+
+	if key == "" {
+		return ""
+	}
+
+	return prefix + key
+}
+
+func isValidLabelKey(key string) bool {
+	for _, r := range key {
+		if labelKeyRune(r) != r {
+			return false
+		}
+	}
+
+	return true
+}
+
+// labelKeyRune returns r if it is allowed in label keys and an underscore otherwise.
+func labelKeyRune(r rune) rune {
+	if isASCIILetter(r) || isASCIIDigit(r) || strings.ContainsRune("_-./", r) {
+		return r
+	}
+
+	return '_'
+}
+
+func isASCIILetter(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+func isASCIIDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+// truncate returns the first n characters of s.
+func truncate(s string, n int) string {
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+
+		i++
+	}
+
+	return s
+}
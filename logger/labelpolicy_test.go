@@ -0,0 +1,169 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+func TestLabels_ValidateStrict(t *testing.T) {
+	policy := logger.LabelPolicy{Mode: logger.LabelModeStrict, MaxKeyLength: 10, MaxValueLength: 5}
+
+	tests := []struct {
+		labels logger.Labels
+		err    error
+	}{
+		{logger.Labels{"msg": "val"}, logger.ErrReservedLabelKey},
+		{logger.Labels{"tags": "val"}, logger.ErrReservedLabelKey},
+		{logger.Labels{"": "val"}, logger.ErrInvalidLabelKey},
+		{logger.Labels{"my key": "val"}, logger.ErrInvalidLabelKey},
+		{logger.Labels{"key=val": "val"}, logger.ErrInvalidLabelKey},
+		{logger.Labels{"a_very_long_key": "val"}, logger.ErrLabelTooLong},
+		{logger.Labels{"key": "too long"}, logger.ErrLabelTooLong},
+	}
+
+	for _, test := range tests {
+		valid, err := test.labels.Validate(policy)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Expected %v for %v, got %v.", test.err, test.labels, err)
+		}
+
+		if len(valid) != 0 {
+			t.Errorf("Invalid labels %v kept.", valid)
+		}
+	}
+}
+
+func TestLabels_ValidateStrictKeepsValid(t *testing.T) {
+	labels := logger.Labels{"product": "Persistor", "app.kubernetes.io/name": "persistor", "level": "high"}
+
+	valid, err := labels.Validate(logger.LabelPolicy{Mode: logger.LabelModeStrict})
+	if !errors.Is(err, logger.ErrReservedLabelKey) {
+		t.Errorf("Expected reserved key error, got %v.", err)
+	}
+
+	expected := logger.Labels{"product": "Persistor", "app.kubernetes.io/name": "persistor"}
+	if !reflect.DeepEqual(valid, expected) {
+		t.Errorf("Got %v, want %v.", valid, expected)
+	}
+
+	if labels["level"] != "high" {
+		t.Error("Original labels modified.")
+	}
+}
+
+func TestLabels_ValidateLenient(t *testing.T) {
+	labels := logger.Labels{
+		"msg":             "m",
+		"caller":          "c",
+		"my key":          "v",
+		"a_very_long_key": "v",
+		"value":           "too long",
+		"":                "empty",
+	}
+
+	valid, err := labels.Validate(logger.LabelPolicy{Mode: logger.LabelModeLenient, MaxKeyLength: 10, MaxValueLength: 5})
+	if err != nil {
+		t.Errorf("Unexpected error %v.", err)
+	}
+
+	expected := logger.Labels{
+		"label_msg":    "m",
+		"label_caller": "c",
+		"my_key":       "v",
+		"a_very_lon":   "v",
+		"value":        "too l",
+	}
+	if !reflect.DeepEqual(valid, expected) {
+		t.Errorf("Got %v, want %v.", valid, expected)
+	}
+}
+
+func TestLabels_ValidateCustomReserved(t *testing.T) {
+	policy := logger.LabelPolicy{Mode: logger.LabelModeLenient, ReservedKeys: []string{"message"}, Prefix: "x_"}
+
+	valid, _ := logger.Labels{"message": "m", "msg": "m"}.Validate(policy)
+
+	expected := logger.Labels{"x_message": "m", "msg": "m"}
+	if !reflect.DeepEqual(valid, expected) {
+		t.Errorf("Got %v, want %v.", valid, expected)
+	}
+}
+
+func TestLabels_ValidateNormalization(t *testing.T) {
+	tests := []struct {
+		normalization logger.LabelNormalization
+		labels        logger.Labels
+		expected      logger.Labels
+	}{
+		{
+			logger.NormalizationPrometheus,
+			logger.Labels{"app.kubernetes.io/name": "a", "9lives": "b", "client-id": "c"},
+			logger.Labels{"app_kubernetes_io_name": "a", "_9lives": "b", "client_id": "c"},
+		},
+		{
+			logger.NormalizationKubernetes,
+			logger.Labels{"App.Example.COM/Name!": "a", "-client id-": "b", strings.Repeat("x", 70): "c"},
+			logger.Labels{"app.example.com/Name": "a", "client_id": "b", strings.Repeat("x", 63): "c"},
+		},
+	}
+
+	for _, test := range tests {
+		valid, err := test.labels.Validate(logger.LabelPolicy{Mode: logger.LabelModeStrict, Normalization: test.normalization})
+		if err != nil {
+			t.Errorf("Unexpected error %v.", err)
+		}
+
+		if !reflect.DeepEqual(valid, test.expected) {
+			t.Errorf("Got %v, want %v.", valid, test.expected)
+		}
+	}
+}
+
+func TestLabels_ValidateDuplicates(t *testing.T) {
+	labels := logger.Labels{"client-id": "a", "client.id": "b"}
+	policy := logger.LabelPolicy{Mode: logger.LabelModeStrict, Normalization: logger.NormalizationPrometheus}
+
+	valid, err := labels.Validate(policy)
+	if !errors.Is(err, logger.ErrDuplicateLabelKey) {
+		t.Errorf("Expected duplicate key error, got %v.", err)
+	}
+
+	if !reflect.DeepEqual(valid, logger.Labels{"client_id": "a"}) {
+		t.Errorf("Got %v.", valid)
+	}
+
+	policy.Mode = logger.LabelModeLenient
+
+	valid, err = labels.Validate(policy)
+	if err != nil || !reflect.DeepEqual(valid, logger.Labels{"client_id": "a"}) {
+		t.Errorf("Got %v, %v.", valid, err)
+	}
+}
+
+func TestLabelPolicy_FieldKey(t *testing.T) {
+	if key := logger.DefaultLabelPolicy.FieldKey("ts"); key != "label_ts" {
+		t.Errorf("Got %s.", key)
+	}
+
+	if key := logger.DefaultLabelPolicy.FieldKey("userId"); key != "userId" {
+		t.Errorf("Got %s.", key)
+	}
+}
@@ -14,6 +14,8 @@
 
 package logger
 
+import "sort"
+
 type Labels map[string]string
 
 type L = Labels
@@ -45,3 +47,15 @@ func (l Labels) Clone() Labels {
 
 	return clone
 }
+
+// Keys returns the label keys in sorted order.
+func (l Labels) Keys() []string {
+	keys := make([]string, 0, len(l))
+	for key := range l {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownLevel is returned when parsing a level name that does not exist.
+var ErrUnknownLevel = errors.New("unknown log level")
+
+var levelNames = map[Level]string{
+	LevelTrace: "trace",
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelPanic: "panic",
+	LevelFatal: "fatal",
+}
+
+// String returns a lower-case name of the level.
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+
+	return "Level(" + strconv.Itoa(int(l)) + ")"
+}
+
+// ParseLevel parses a case-insensitive level name, such as "info" or "WARN".
+func ParseLevel(text string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(text))
+
+	for lvl, lvlName := range levelNames {
+		if lvlName == name {
+			return lvl, nil
+		}
+	}
+
+	return LevelInfo, fmt.Errorf("%w: %q", ErrUnknownLevel, text)
+}
+
+// MarshalText marshals the level to its name.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText unmarshals a level name, making levels usable in JSON and YAML configuration.
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = lvl
+
+	return nil
+}
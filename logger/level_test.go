@@ -0,0 +1,91 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected logger.Level
+	}{
+		{"trace", logger.LevelTrace},
+		{"debug", logger.LevelDebug},
+		{"info", logger.LevelInfo},
+		{"WARN", logger.LevelWarn},
+		{" error ", logger.LevelError},
+		{"panic", logger.LevelPanic},
+		{"fatal", logger.LevelFatal},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.text, func(t *testing.T) {
+			lvl, err := logger.ParseLevel(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if lvl != test.expected {
+				t.Errorf("ParseLevel(%s)=%s, want %s.", test.text, lvl, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseLevel_Unknown(t *testing.T) {
+	if _, err := logger.ParseLevel("verbose"); !errors.Is(err, logger.ErrUnknownLevel) {
+		t.Errorf("ParseLevel(verbose) error %v, want %v.", err, logger.ErrUnknownLevel)
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	if logger.LevelWarn.String() != "warn" {
+		t.Errorf("Wrong name %s, want warn.", logger.LevelWarn)
+	}
+
+	if logger.Level(42).String() != "Level(42)" {
+		t.Errorf("Wrong name %s, want Level(42).", logger.Level(42))
+	}
+}
+
+func TestLevel_JSON(t *testing.T) {
+	payload := struct {
+		Level logger.Level `json:"level"`
+	}{}
+
+	if err := json.Unmarshal([]byte(`{"level":"debug"}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Level != logger.LevelDebug {
+		t.Errorf("Wrong level %s, want debug.", payload.Level)
+	}
+
+	out, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != `{"level":"debug"}` {
+		t.Errorf("Wrong JSON %s.", out)
+	}
+}
@@ -12,45 +12,85 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package logger defines an interface for logging with various levels and methods for structured logging, including Info, Warn, Error, Fatal, and Panic, as well as options for attaching fields and handling panics, along with methods for flushing and closing the logger.
+// Package logger defines an interface for logging with various levels and methods for structured logging, including Trace, Debug, Info, Warn, Error, Fatal, and Panic, as well as options for attaching fields and handling panics, along with methods for flushing and closing the logger.
 package logger
 
+import "context"
+
 type Fields map[string]interface{}
 
 type F = Fields
 
 type Log interface {
+	Trace(msg string)
+
+	Tracew(msg string, fields Fields)
+
+	TraceWith(msg string, fields ...Field)
+
+	Debug(msg string)
+
+	Debugw(msg string, fields Fields)
+
+	DebugWith(msg string, fields ...Field)
+
 	Info(msg string)
 
 	Infow(msg string, fields Fields)
 
+	InfoWith(msg string, fields ...Field)
+
 	Warn(msg string)
 
 	Warnw(msg string, fields Fields)
 
+	WarnWith(msg string, fields ...Field)
+
 	Error(msg string, code uint64)
 
 	Errorw(msg string, code uint64, fields Fields)
 
+	ErrorWith(msg string, code uint64, fields ...Field)
+
+	// ErrorE logs an error entry that describes err, including its causes and the stack trace where it was created.
+	ErrorE(msg string, code uint64, err error, fields Fields)
+
 	Fatal(msg string, code uint64)
 
 	Fatalw(msg string, code uint64, fields Fields)
 
+	FatalWith(msg string, code uint64, fields ...Field)
+
 	Panic(msg string, code uint64)
 
 	Panicw(msg string, code uint64, fields Fields)
 
+	PanicWith(msg string, code uint64, fields ...Field)
+
 	PanicLogger()
 
+	With(fields Fields) Log
+
+	WithLabels(labels Labels) Log
+
+	WithContext(ctx context.Context) Log
+
 	Flush()
 
-	Close()
+	// Sync writes out buffered entries and syncs the outputs, returning the errors of doing so.
+	Sync() error
+
+	// Close flushes the log and releases the outputs it owns. Entries logged afterwards are discarded
+	// or written to a fallback output. Closing an already closed Log does nothing.
+	Close() error
 }
 
 type Level int8
 
 const (
-	LevelInfo = iota
+	LevelTrace Level = iota - 2
+	LevelDebug
+	LevelInfo
 	LevelWarn
 	LevelError
 	LevelPanic
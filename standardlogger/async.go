@@ -0,0 +1,325 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// OverflowPolicy defines what happens to an entry written while the async buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the logging call wait until the buffer has room.
+	OverflowBlock OverflowPolicy = iota + 1
+	// OverflowDropOldest drops the oldest buffered entry to make room for the new one.
+	OverflowDropOldest
+	// OverflowDropNewest drops the new entry.
+	OverflowDropNewest
+)
+
+type asyncSettings struct {
+	size          int
+	flushInterval time.Duration
+	policy        OverflowPolicy
+}
+
+// WithAsync returns Option that writes entries to the outputs in the background.
+// Encoded entries are kept in a buffer of size entries and written every flushInterval,
+// or as soon as the buffer fills up. The policy decides what happens to entries written while the buffer is full.
+// Flush and Close write out all buffered entries before returning, as do panic and fatal entries.
+func WithAsync(size int, flushInterval time.Duration, policy OverflowPolicy) Option {
+	return func(ls *loggerSettings) {
+		ls.async = &asyncSettings{
+			size:          size,
+			flushInterval: flushInterval,
+			policy:        policy,
+		}
+	}
+}
+
+// asyncOutputs holds the async writers of a root logger.
+type asyncOutputs struct {
+	writers []*asyncWriter
+}
+
+// newAsyncOutputs wraps the outputs with async writers, sharing a single writer if both are the same.
+// Errors of background writes are passed to errorHandler if set, otherwise they are returned by the next Sync.
+func newAsyncOutputs(settings *asyncSettings, errorHandler func(error), output, errorOutput zapcore.WriteSyncer) (zapcore.WriteSyncer, zapcore.WriteSyncer, *asyncOutputs) {
+	asyncOutput := newAsyncWriter(output, settings, errorHandler)
+
+	if output == errorOutput {
+		return asyncOutput, asyncOutput, &asyncOutputs{writers: []*asyncWriter{asyncOutput}}
+	}
+
+	asyncErrorOutput := newAsyncWriter(errorOutput, settings, errorHandler)
+
+	return asyncOutput, asyncErrorOutput, &asyncOutputs{writers: []*asyncWriter{asyncOutput, asyncErrorOutput}}
+}
+
+func (o *asyncOutputs) dropped() uint64 {
+	if o == nil {
+		return 0
+	}
+
+	var dropped uint64
+	for _, w := range o.writers {
+		dropped += atomic.LoadUint64(&w.dropped)
+	}
+
+	return dropped
+}
+
+func (o *asyncOutputs) close() error {
+	if o == nil {
+		return nil
+	}
+
+	var err error
+	for _, w := range o.writers {
+		err = multierr.Append(err, w.Close())
+	}
+
+	return err
+}
+
+// asyncWriter is a zapcore.WriteSyncer that buffers writes in a ring buffer and writes them to out in the background.
+type asyncWriter struct {
+	// dropped is accessed atomically and kept first, so it is 64-bit aligned on 32-bit platforms.
+	dropped uint64
+
+	out          zapcore.WriteSyncer
+	settings     asyncSettings
+	errorHandler func(error)
+
+	mu      sync.Mutex
+	notFull *sync.Cond
+	ring    [][]byte
+	head    int
+	count   int
+	closed  bool
+
+	// writeMu serializes writes to out, so entries are written in order, and guards batch.
+	writeMu sync.Mutex
+	batch   []byte
+
+	// err is the first error of background writes since the last Sync, kept if there is no error handler.
+	errMu sync.Mutex
+	err   error
+
+	kick chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newAsyncWriter(out zapcore.WriteSyncer, settings *asyncSettings, errorHandler func(error)) *asyncWriter {
+	size := settings.size
+	if size < 1 {
+		size = 1
+	}
+
+	w := &asyncWriter{
+		out:          out,
+		settings:     *settings,
+		errorHandler: errorHandler,
+		ring:         make([][]byte, size),
+		kick:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+	w.notFull = sync.NewCond(&w.mu)
+
+	w.wg.Add(1)
+
+	go w.run()
+
+	return w
+}
+
+// Write buffers a copy of p, since zap reuses the buffer after Write returns.
+// Once the writer is closed, p is written to out directly.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mu.Lock()
+
+	for !w.closed && w.count == len(w.ring) {
+		w.trigger()
+
+		if w.settings.policy == OverflowDropNewest {
+			atomic.AddUint64(&w.dropped, 1)
+			w.mu.Unlock()
+
+			return len(p), nil
+		}
+
+		if w.settings.policy == OverflowDropOldest {
+			w.ring[w.head] = nil
+			w.head = (w.head + 1) % len(w.ring)
+			w.count--
+			atomic.AddUint64(&w.dropped, 1)
+
+			break
+		}
+
+		w.notFull.Wait()
+	}
+
+	if w.closed {
+		w.mu.Unlock()
+
+		w.writeMu.Lock()
+		defer w.writeMu.Unlock()
+
+		return w.out.Write(p) //nolint:wrapcheck //returned as is to satisfy io.Writer
+	}
+
+	w.ring[(w.head+w.count)%len(w.ring)] = entry
+	w.count++
+
+	if w.count == len(w.ring) {
+		w.trigger()
+	}
+
+	w.mu.Unlock()
+
+	return len(p), nil
+}
+
+// Sync writes out all buffered entries and syncs out.
+// It also returns the first error of background writes since the last Sync, unless it was passed to the error handler.
+func (w *asyncWriter) Sync() error {
+	err := w.drain()
+
+	w.errMu.Lock()
+	if w.err != nil {
+		err = w.err
+		w.err = nil
+	}
+	w.errMu.Unlock()
+
+	if syncErr := w.out.Sync(); err == nil {
+		err = syncErr
+	}
+
+	return err
+}
+
+// Close stops the background writes and writes out all buffered entries.
+// Closing an already closed writer only syncs it.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+		w.notFull.Broadcast()
+	}
+
+	w.mu.Unlock()
+
+	w.wg.Wait()
+
+	return w.Sync()
+}
+
+func (w *asyncWriter) trigger() {
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (w *asyncWriter) run() {
+	defer w.wg.Done()
+
+	var tick <-chan time.Time
+
+	if w.settings.flushInterval > 0 {
+		ticker := time.NewTicker(w.settings.flushInterval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-tick:
+		case <-w.kick:
+		}
+
+		if err := w.drain(); err != nil {
+			w.handleError(err)
+		}
+	}
+}
+
+func (w *asyncWriter) handleError(err error) {
+	if w.errorHandler != nil {
+		w.errorHandler(err)
+
+		return
+	}
+
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// drain writes all buffered entries to out in a single write.
+func (w *asyncWriter) drain() error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	w.mu.Lock()
+
+	w.batch = w.batch[:0]
+	for ; w.count > 0; w.count-- {
+		w.batch = append(w.batch, w.ring[w.head]...)
+		w.ring[w.head] = nil
+		w.head = (w.head + 1) % len(w.ring)
+	}
+
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	if len(w.batch) == 0 {
+		return nil
+	}
+
+	_, err := w.out.Write(w.batch)
+
+	return err //nolint:wrapcheck //returned as is to satisfy zapcore.WriteSyncer
+}
+
+// DroppedByBuffer returns the number of log entries dropped because the async buffer was full
+// since the root logger was created.
+func (l *StandardLog) DroppedByBuffer() uint64 {
+	if l.lifecycle == nil {
+		return 0
+	}
+
+	return l.lifecycle.async.dropped()
+}
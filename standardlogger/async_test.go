@@ -0,0 +1,257 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+// blockingWriter blocks writes until released, after signaling that a write started.
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	writing chan struct{}
+	release chan struct{}
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{writing: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.writing <- struct{}{}
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.String()
+}
+
+func messages(output string) []string {
+	var msgs []string
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if i := strings.Index(line, `"msg":"`); i >= 0 {
+			msg := line[i+len(`"msg":"`):]
+			msgs = append(msgs, msg[:strings.Index(msg, `"`)])
+		}
+	}
+
+	return msgs
+}
+
+func newAsyncLogger(w *blockingWriter, policy standardlogger.OverflowPolicy) logger.Log {
+	return standardlogger.New(nil,
+		standardlogger.WithSingleOutput(w),
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithAsync(2, time.Hour, policy),
+		standardlogger.WithoutSampling(),
+	)
+}
+
+// fillWhileWriting logs two entries that are picked up by a write that blocks, then fills the buffer with two more.
+func fillWhileWriting(t *testing.T, log logger.Log, w *blockingWriter) {
+	t.Helper()
+
+	log.Info("1")
+	log.Info("2")
+
+	select {
+	case <-w.writing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Full buffer wasn't written.")
+	}
+
+	log.Info("3")
+	log.Info("4")
+}
+
+func TestWithAsync_Flush(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithAsync(10, time.Hour, standardlogger.OverflowBlock),
+	)
+
+	log.Info("1")
+	log.Warn("2")
+	log.Error("3", 0)
+
+	if buf.Len() != 0 {
+		t.Fatalf("Entries written before Flush: %s", buf.String())
+	}
+
+	log.Flush()
+
+	if msgs := messages(buf.String()); strings.Join(msgs, ",") != "1,2,3" {
+		t.Errorf("Wrong entries %v.", msgs)
+	}
+}
+
+func TestWithAsync_FlushInterval(t *testing.T) {
+	w := newBlockingWriter()
+	close(w.release)
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(w),
+		standardlogger.WithAsync(10, 10*time.Millisecond, standardlogger.OverflowBlock),
+	)
+
+	log.Info("1")
+
+	select {
+	case <-w.writing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Entry wasn't written after the flush interval.")
+	}
+}
+
+func TestWithAsync_DropNewest(t *testing.T) {
+	w := newBlockingWriter()
+	log := newAsyncLogger(w, standardlogger.OverflowDropNewest)
+
+	fillWhileWriting(t, log, w)
+	log.Info("5")
+
+	close(w.release)
+	log.Flush()
+
+	if msgs := messages(w.String()); strings.Join(msgs, ",") != "1,2,3,4" {
+		t.Errorf("Wrong entries %v.", msgs)
+	}
+
+	if dropped := log.(*standardlogger.StandardLog).DroppedByBuffer(); dropped != 1 {
+		t.Errorf("Expected 1 dropped entry, got %d.", dropped)
+	}
+}
+
+func TestWithAsync_DropOldest(t *testing.T) {
+	w := newBlockingWriter()
+	log := newAsyncLogger(w, standardlogger.OverflowDropOldest)
+
+	fillWhileWriting(t, log, w)
+	log.Info("5")
+
+	close(w.release)
+	log.Flush()
+
+	if msgs := messages(w.String()); strings.Join(msgs, ",") != "1,2,4,5" {
+		t.Errorf("Wrong entries %v.", msgs)
+	}
+
+	if dropped := log.(*standardlogger.StandardLog).DroppedByBuffer(); dropped != 1 {
+		t.Errorf("Expected 1 dropped entry, got %d.", dropped)
+	}
+}
+
+func TestWithAsync_Block(t *testing.T) {
+	w := newBlockingWriter()
+	log := newAsyncLogger(w, standardlogger.OverflowBlock)
+
+	fillWhileWriting(t, log, w)
+
+	logged := make(chan struct{})
+
+	go func() {
+		log.Info("5")
+		close(logged)
+	}()
+
+	select {
+	case <-logged:
+		t.Fatal("Logging didn't block on a full buffer.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(w.release)
+	<-logged
+	log.Flush()
+
+	if msgs := messages(w.String()); strings.Join(msgs, ",") != "1,2,3,4,5" {
+		t.Errorf("Wrong entries %v.", msgs)
+	}
+
+	if dropped := log.(*standardlogger.StandardLog).DroppedByBuffer(); dropped != 0 {
+		t.Errorf("Expected no dropped entries, got %d.", dropped)
+	}
+}
+
+func TestWithAsync_Close(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithAsync(10, time.Hour, standardlogger.OverflowBlock),
+	)
+
+	log.Info("1")
+	log.Close()
+
+	if msgs := messages(buf.String()); strings.Join(msgs, ",") != "1" {
+		t.Errorf("Wrong entries after Close %v.", msgs)
+	}
+}
+
+func TestWithAsync_DroppedByBufferConcurrent(t *testing.T) {
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&bytes.Buffer{}),
+		standardlogger.WithAsync(1, time.Hour, standardlogger.OverflowDropNewest),
+		standardlogger.WithoutSampling(),
+	).(*standardlogger.StandardLog)
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 1000; i++ {
+			log.Info("msg")
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 1000; i++ {
+			log.DroppedByBuffer()
+		}
+	}()
+
+	wg.Wait()
+
+	if err := log.Close(); err != nil {
+		t.Errorf("Unexpected error %v.", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const azureCodeKey = "code"
+
+// Application Insights SeverityLevel values.
+const (
+	azureSeverityVerbose = iota
+	azureSeverityInformation
+	azureSeverityWarning
+	azureSeverityError
+	azureSeverityCritical
+)
+
+// azureSchema follows the Application Insights telemetry shape, so entries ingested into Log Analytics map to
+// severityLevel, customDimensions and exceptions, see https://learn.microsoft.com/en-us/azure/azure-monitor/app/data-model-complete.
+var azureSchema = &schema{
+	codeField: func(code uint64) zap.Field {
+		return zap.Uint64(azureCodeKey, code)
+	},
+	labelFields: func(labels logger.Labels) []zap.Field {
+		return []zap.Field{
+			zap.Object("customDimensions", labelsObject(labels)),
+			zap.Strings(tagsKey, GetLabelsKeys(labels)),
+		}
+	},
+	encoderConfig: func(conf *zapcore.EncoderConfig) {
+		conf.TimeKey = "timestamp"
+		conf.LevelKey = "severityLevel"
+		conf.MessageKey = "message"
+		conf.EncodeLevel = azureSeverityEncoder
+	},
+	encodeEntry: func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		if ent.Level < zapcore.ErrorLevel {
+			return ent, fields
+		}
+
+		// the code, the type and message of the error and the stack trace are moved into the exception.
+		exception := azureException{entry: ent, stack: ent.Stack}
+
+		rest := make([]zapcore.Field, 0, len(fields))
+
+		for _, field := range fields {
+			if field.Key == azureCodeKey && field.Type == zapcore.Uint64Type && !exception.hasCode {
+				exception.code = strconv.FormatUint(uint64(field.Integer), 10)
+				exception.hasCode = true
+
+				continue
+			}
+
+			if obj, ok := field.Interface.(errorObject); ok && field.Key == errorKey && !exception.hasError {
+				exception.errorType = errorType(obj.err)
+				exception.errorMessage = obj.redact(obj.err.Error())
+				exception.hasError = true
+
+				// the error's own stack trace replaces the one of the entry.
+				if obj.stack != "" {
+					exception.stack = obj.stack
+					field = zap.Object(errorKey, obj.withoutStack())
+				}
+			}
+
+			rest = append(rest, field)
+		}
+
+		ent.Stack = ""
+
+		return ent, prependFields([]zap.Field{zap.Array("exceptions", exception)}, rest)
+	},
+	reservedKeys: []string{
+		"timestamp", "severityLevel", "message", "caller", "customDimensions", "exceptions", azureCodeKey, tagsKey,
+	},
+}
+
+// azureSeverityEncoder encodes levels as Application Insights SeverityLevel numbers.
+func azureSeverityEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	var severity int
+
+	switch {
+	case lvl <= zapcore.DebugLevel:
+		severity = azureSeverityVerbose
+	case lvl == zapcore.InfoLevel:
+		severity = azureSeverityInformation
+	case lvl == zapcore.WarnLevel:
+		severity = azureSeverityWarning
+	case lvl == zapcore.ErrorLevel:
+		severity = azureSeverityError
+	default:
+		severity = azureSeverityCritical
+	}
+
+	enc.AppendInt(severity)
+}
+
+// azureException marshals an error entry as a single element exceptions array.
+// The error code becomes the problemId, which Application Insights groups exceptions by.
+// The type and message are those of the error of ErrorE or logger.Err, or the level and the message of the entry.
+type azureException struct {
+	entry        zapcore.Entry
+	code         string
+	hasCode      bool
+	errorType    string
+	errorMessage string
+	hasError     bool
+	stack        string
+}
+
+func (e azureException) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return enc.AppendObject(e) //nolint:wrapcheck //encoder errors are handled by zap
+}
+
+func (e azureException) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if e.hasError {
+		enc.AddString("typeName", e.errorType)
+		enc.AddString("message", e.errorMessage)
+	} else {
+		level := levelName(e.entry.Level)
+
+		enc.AddString("typeName", strings.ToUpper(level[:1])+level[1:])
+		enc.AddString("message", e.entry.Message)
+	}
+
+	if e.hasCode {
+		enc.AddString("problemId", e.code)
+	}
+
+	azureSeverityEncoder(e.entry.Level, severityField{enc: enc})
+
+	enc.AddBool("hasFullStack", e.stack != "")
+
+	if e.stack != "" {
+		enc.AddString("stack", e.stack)
+	}
+
+	return nil
+}
+
+// severityField adapts an ObjectEncoder so a LevelEncoder can write the severityLevel key.
+type severityField struct {
+	zapcore.PrimitiveArrayEncoder
+	enc zapcore.ObjectEncoder
+}
+
+func (f severityField) AppendInt(v int) {
+	f.enc.AddInt("severityLevel", v)
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestWithProfile_AzureSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(logger.Log)
+		expected float64
+	}{
+		{"info", func(log logger.Log) { log.Info("msg") }, 1},
+		{"warn", func(log logger.Log) { log.Warn("msg") }, 2},
+		{"error", func(log logger.Log) { log.Error("msg", 0) }, 3},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			entry := logProfileEntry(t, standardlogger.ProfileAzure, test.log)
+
+			if entry["severityLevel"] != test.expected {
+				t.Errorf("Wrong severityLevel %v, want %v.", entry["severityLevel"], test.expected)
+			}
+		})
+	}
+}
+
+func TestWithProfile_Azure(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileAzure, func(log logger.Log) {
+		log.Infow("Info msg", logger.F{"id": "1"})
+	})
+
+	if entry["message"] != "Info msg" {
+		t.Errorf("Wrong message %v.", entry["message"])
+	}
+
+	if entry["id"] != "1" {
+		t.Errorf("Wrong field id %v.", entry["id"])
+	}
+
+	for _, key := range []string{"ts", "level", "msg", "product", "exceptions"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Key %s present.", key)
+		}
+	}
+
+	dimensions, ok := entry["customDimensions"].(map[string]interface{})
+	if !ok || dimensions["product"] != "Persistor" {
+		t.Errorf("Wrong customDimensions %v.", entry["customDimensions"])
+	}
+}
+
+func TestWithProfile_AzureException(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileAzure, func(log logger.Log) {
+		log.Errorw("Error msg", 1000, logger.F{"id": "1"})
+	})
+
+	for _, key := range []string{"code", "stacktrace"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Key %s present.", key)
+		}
+	}
+
+	if entry["id"] != "1" {
+		t.Errorf("Wrong field id %v.", entry["id"])
+	}
+
+	exceptions, ok := entry["exceptions"].([]interface{})
+	if !ok || len(exceptions) != 1 {
+		t.Fatalf("Wrong exceptions %v.", entry["exceptions"])
+	}
+
+	exception, ok := exceptions[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Wrong exception %v.", exceptions[0])
+	}
+
+	expected := map[string]interface{}{
+		"typeName":      "Error",
+		"message":       "Error msg",
+		"problemId":     "1000",
+		"severityLevel": float64(3),
+		"hasFullStack":  true,
+	}
+	for key, value := range expected {
+		if exception[key] != value {
+			t.Errorf("Wrong exception %s %v, want %v.", key, exception[key], value)
+		}
+	}
+
+	if stack, _ := exception["stack"].(string); stack == "" {
+		t.Error("Exception stack missing.")
+	}
+}
+
+func TestWithProfile_AzureExceptionFromError(t *testing.T) {
+	entry := logErrorEntry(t, standardlogger.ProfileAzure, newOriginError())
+
+	exceptions, _ := entry["exceptions"].([]interface{})
+	exception, _ := exceptions[0].(map[string]interface{})
+
+	if exception["typeName"] != "*errors.fundamental" || exception["message"] != "connection refused" {
+		t.Errorf("Exception not built from the error: %v.", exception)
+	}
+
+	if stack, _ := exception["stack"].(string); !strings.Contains(stack, "newOriginError") {
+		t.Errorf("Wrong exception stack %q.", stack)
+	}
+}
+
+func TestWithProfile_AzureErrorFieldsOfOtherCores(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil,
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithProfile(standardlogger.ProfileAzure),
+	)
+
+	log.ErrorE("Error msg", 1000, newOriginError(), nil)
+
+	for _, field := range logs.All()[0].Context {
+		if field.Key == "" {
+			t.Errorf("Field without a key in %v.", logs.All()[0].Context)
+		}
+	}
+
+	object, _ := logs.All()[0].ContextMap()["error"].(map[string]interface{})
+	if stack, _ := object["stack_trace"].(string); !strings.Contains(stack, "newOriginError") {
+		t.Errorf("Error object without its stack trace: %v.", object)
+	}
+}
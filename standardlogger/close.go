@@ -0,0 +1,188 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"io"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFallbackOutput returns Option that writes entries logged after Close to w, e.g. os.Stderr.
+// By default, they are discarded.
+func WithFallbackOutput(w io.Writer) Option {
+	return func(ls *loggerSettings) {
+		ls.fallbackOutput = w
+	}
+}
+
+// newFallbackCore returns the core of the fallback output, or nil if there is none.
+func newFallbackCore(settings loggerSettings, enabler zapcore.LevelEnabler) zapcore.Core {
+	if settings.fallbackOutput == nil {
+		return nil
+	}
+
+	return zapcore.NewCore(newEncoder(settings), zapcore.Lock(addSync(settings.fallbackOutput)), enabler)
+}
+
+// lifecycle is shared by a root logger and its children and tracks whether they were closed.
+type lifecycle struct {
+	// closeMu serializes calls to close.
+	closeMu sync.Mutex
+	// mu is held for reading while writing entries, so Close waits for the writes in progress.
+	mu     sync.RWMutex
+	closed bool
+
+	cores        []zapcore.Core
+	async        *asyncOutputs
+	closers      []io.Closer
+	errorHandler func(error)
+}
+
+// close syncs the cores and closes the async writers and owned outputs, collecting all errors.
+// The async writers are closed before taking the lock, so error handlers they call may log through the logger.
+func (lc *lifecycle) close() error {
+	lc.closeMu.Lock()
+	defer lc.closeMu.Unlock()
+
+	lc.mu.RLock()
+	closed := lc.closed
+	lc.mu.RUnlock()
+
+	if closed {
+		return nil
+	}
+
+	err := lc.async.close()
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.closed = true
+
+	for _, core := range lc.cores {
+		err = multierr.Append(err, core.Sync())
+	}
+
+	for _, closer := range lc.closers {
+		err = multierr.Append(err, closer.Close())
+	}
+
+	return err
+}
+
+// lifecycleCore writes entries to the cores enabled for their level until the lifecycle is closed,
+// and to the fallback core, if any, afterwards.
+type lifecycleCore struct {
+	cores     []zapcore.Core
+	fallback  zapcore.Core
+	lifecycle *lifecycle
+}
+
+func (c *lifecycleCore) Enabled(lvl zapcore.Level) bool {
+	for _, core := range c.cores {
+		if core.Enabled(lvl) {
+			return true
+		}
+	}
+
+	return c.fallback != nil && c.fallback.Enabled(lvl)
+}
+
+func (c *lifecycleCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &lifecycleCore{
+		cores:     make([]zapcore.Core, len(c.cores)),
+		lifecycle: c.lifecycle,
+	}
+
+	for i, core := range c.cores {
+		clone.cores[i] = core.With(fields)
+	}
+
+	if c.fallback != nil {
+		clone.fallback = c.fallback.With(fields)
+	}
+
+	return clone
+}
+
+func (c *lifecycleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *lifecycleCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.write(ent, fields)
+
+	// without a handler, zap writes the error to stderr.
+	// The handler runs without the lock held, so it may log through the same logger while Close waits for the lock.
+	if err != nil && c.lifecycle.errorHandler != nil {
+		c.lifecycle.errorHandler(err)
+
+		return nil
+	}
+
+	return err
+}
+
+// write writes the entry to the cores, or to the fallback once the lifecycle is closed.
+func (c *lifecycleCore) write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.lifecycle.mu.RLock()
+	defer c.lifecycle.mu.RUnlock()
+
+	if c.lifecycle.closed {
+		if c.fallback == nil || !c.fallback.Enabled(ent.Level) {
+			return nil
+		}
+
+		return c.fallback.Write(ent, fields) //nolint:wrapcheck //returned as is to satisfy zapcore.Core
+	}
+
+	var err error
+
+	for _, core := range c.cores {
+		if core.Enabled(ent.Level) {
+			err = multierr.Append(err, core.Write(ent, fields))
+		}
+	}
+
+	return err
+}
+
+func (c *lifecycleCore) Sync() error {
+	c.lifecycle.mu.RLock()
+	defer c.lifecycle.mu.RUnlock()
+
+	if c.lifecycle.closed {
+		if c.fallback == nil {
+			return nil
+		}
+
+		return c.fallback.Sync() //nolint:wrapcheck //returned as is to satisfy zapcore.Core
+	}
+
+	var err error
+
+	for _, core := range c.cores {
+		err = multierr.Append(err, core.Sync())
+	}
+
+	return err
+}
@@ -0,0 +1,296 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+	"github.com/dataphos/lib-logger/standardlogger/filesink"
+	"github.com/dataphos/lib-logger/standardlogger/otlp"
+)
+
+var errSync = errors.New("sync failed")
+
+// closeAwareWriter counts writes that happen after the logger's Close returned.
+type closeAwareWriter struct {
+	mu               sync.Mutex
+	buf              bytes.Buffer
+	closed           int32
+	writesAfterClose int32
+	syncErr          error
+}
+
+func (w *closeAwareWriter) Write(p []byte) (int, error) {
+	if atomic.LoadInt32(&w.closed) == 1 {
+		atomic.AddInt32(&w.writesAfterClose, 1)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Write(p)
+}
+
+func (w *closeAwareWriter) Sync() error {
+	return w.syncErr
+}
+
+func (w *closeAwareWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.String()
+}
+
+func TestStandardLog_CloseDiscardsLaterEntries(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := standardlogger.New(nil, standardlogger.WithSingleOutput(&buf))
+
+	log.Info("before")
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v.", err)
+	}
+
+	if err := log.Close(); err != nil {
+		t.Errorf("Second Close failed: %v.", err)
+	}
+
+	log.Info("after")
+	log.With(logger.F{"id": 1}).Warn("after")
+	log.Flush()
+
+	if !strings.Contains(buf.String(), "before") || strings.Contains(buf.String(), "after") {
+		t.Errorf("Wrong output %s", buf.String())
+	}
+}
+
+func TestStandardLog_CloseChild(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := standardlogger.New(nil, standardlogger.WithSingleOutput(&buf))
+	child := log.WithLabels(logger.Labels{"component": "writer"})
+
+	if err := child.Close(); err != nil {
+		t.Fatalf("Close failed: %v.", err)
+	}
+
+	log.Info("after")
+
+	if buf.Len() != 0 {
+		t.Errorf("Root logger wasn't closed: %s", buf.String())
+	}
+}
+
+func TestWithFallbackOutput(t *testing.T) {
+	var buf, fallback bytes.Buffer
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithFallbackOutput(&fallback),
+	)
+
+	log.Info("before")
+	log.Close()
+	log.Info("after")
+
+	if strings.Contains(buf.String(), "after") || strings.Contains(fallback.String(), "before") {
+		t.Errorf("Wrong outputs %s, %s", buf.String(), fallback.String())
+	}
+
+	if !strings.Contains(fallback.String(), "after") {
+		t.Errorf("Entry missing from fallback output %s", fallback.String())
+	}
+}
+
+func TestStandardLog_CloseOwnedOutputs(t *testing.T) {
+	sink, err := filesink.New(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := otlp.New(otlp.DefaultEndpoint, otlp.WithFlushInterval(time.Hour))
+
+	log := standardlogger.New(nil, standardlogger.WithFileOutput(sink), standardlogger.WithOTLPExporter(exporter))
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v.", err)
+	}
+
+	if _, err := sink.Write([]byte("entry\n")); !errors.Is(err, filesink.ErrClosed) {
+		t.Errorf("File sink wasn't closed, write returned %v.", err)
+	}
+
+	if err := exporter.Sync(); !errors.Is(err, otlp.ErrClosed) {
+		t.Errorf("Exporter wasn't closed, sync returned %v.", err)
+	}
+}
+
+func TestStandardLog_CloseError(t *testing.T) {
+	w := &closeAwareWriter{syncErr: errSync}
+
+	log := standardlogger.New(nil, standardlogger.WithSingleOutput(w))
+
+	if err := log.Close(); !errors.Is(err, errSync) {
+		t.Errorf("Expected sync error, got %v.", err)
+	}
+}
+
+func TestStandardLog_ConcurrentClose(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []standardlogger.Option
+	}{
+		{"sync", nil},
+		{"async", []standardlogger.Option{standardlogger.WithAsync(16, time.Millisecond, standardlogger.OverflowBlock)}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			w := &closeAwareWriter{}
+
+			opts := append([]standardlogger.Option{
+				standardlogger.WithSingleOutput(w),
+				standardlogger.WithoutSampling(),
+			}, test.opts...)
+			log := standardlogger.New(nil, opts...)
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					for j := 0; j < 200; j++ {
+						log.Infow("Info msg", logger.F{"j": j})
+					}
+				}()
+			}
+
+			time.Sleep(time.Millisecond)
+
+			var closeErrs int32
+
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					if err := log.Close(); err != nil {
+						atomic.AddInt32(&closeErrs, 1)
+					}
+
+					// from here on, the goroutines still logging must not reach the writer.
+					atomic.StoreInt32(&w.closed, 1)
+				}()
+			}
+
+			wg.Wait()
+
+			if closeErrs != 0 {
+				t.Errorf("Close failed %d times.", closeErrs)
+			}
+
+			log.Info("after")
+			log.Flush()
+
+			if writes := atomic.LoadInt32(&w.writesAfterClose); writes != 0 {
+				t.Errorf("%d writes after Close.", writes)
+			}
+		})
+	}
+}
+
+func TestStandardLog_CloseWhileErrorHandlerLogs(t *testing.T) {
+	var (
+		log  logger.Log
+		once sync.Once
+	)
+
+	handled := make(chan struct{})
+
+	log = standardlogger.New(nil,
+		standardlogger.WithSingleOutput(failingWriter{}),
+		standardlogger.WithErrorHandler(func(error) {
+			once.Do(func() {
+				go log.Close()
+
+				// gives Close the time to wait for the write to finish.
+				time.Sleep(50 * time.Millisecond)
+				log.Warn("Write failed")
+				close(handled)
+			})
+		}),
+	)
+
+	go log.Info("Info msg")
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Logging from the error handler deadlocked with Close.")
+	}
+}
+
+func TestStandardLog_CloseWhileAsyncErrorHandlerLogs(t *testing.T) {
+	var (
+		log     logger.Log
+		handled int32
+	)
+
+	closed := make(chan struct{})
+
+	log = standardlogger.New(nil,
+		standardlogger.WithSingleOutput(failingWriter{}),
+		standardlogger.WithAsync(16, 10*time.Millisecond, standardlogger.OverflowBlock),
+		standardlogger.WithErrorHandler(func(error) {
+			// the warning fails to be written as well, calling the handler again.
+			if !atomic.CompareAndSwapInt32(&handled, 0, 1) {
+				return
+			}
+
+			go func() {
+				log.Close()
+				close(closed)
+			}()
+
+			// gives Close the time to wait for the background writes to stop.
+			time.Sleep(50 * time.Millisecond)
+			log.Warn("Write failed")
+		}),
+	)
+
+	log.Info("Info msg")
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Logging from the async error handler deadlocked with Close.")
+	}
+}
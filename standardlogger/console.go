@@ -0,0 +1,235 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	consoleTimeFormat  = "15:04:05.000"
+	consoleCallerWidth = 32
+	consoleLevelWidth  = 5
+	consoleStackIndent = "    "
+	tagsKey            = "tags"
+
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorGray    = "\x1b[90m"
+)
+
+var bufferPool = buffer.NewPool()
+
+// consoleEncoder renders entries as a single human-readable line followed by an indented stack trace.
+// Labels are rendered as [key=value ...] and the tags field is omitted, since it repeats the label keys.
+type consoleEncoder struct {
+	*zapcore.MapObjectEncoder
+	color bool
+}
+
+func newConsoleEncoder(color bool) *consoleEncoder {
+	return &consoleEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		color:            color,
+	}
+}
+
+func (e *consoleEncoder) Clone() zapcore.Encoder {
+	return e.clone()
+}
+
+func (e *consoleEncoder) clone() *consoleEncoder {
+	clone := newConsoleEncoder(e.color)
+	for key, val := range e.Fields {
+		clone.Fields[key] = val
+	}
+
+	return clone
+}
+
+func (e *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	buf := bufferPool.Get()
+
+	e.colored(buf, colorGray, ent.Time.Format(consoleTimeFormat))
+	buf.AppendByte(' ')
+
+	level := strings.ToUpper(levelName(ent.Level))
+	e.colored(buf, levelColor(ent.Level), level+strings.Repeat(" ", maxInt(consoleLevelWidth-len(level), 0)))
+	buf.AppendByte(' ')
+
+	if ent.Caller.Defined {
+		caller := ent.Caller.TrimmedPath()
+		e.colored(buf, colorGray, caller+strings.Repeat(" ", maxInt(consoleCallerWidth-len(caller), 0)))
+		buf.AppendByte(' ')
+	}
+
+	buf.AppendString(ent.Message)
+	enc.appendContext(buf)
+
+	if ent.Stack != "" {
+		for _, line := range strings.Split(ent.Stack, "\n") {
+			buf.AppendByte('\n')
+			buf.AppendString(consoleStackIndent)
+			// file paths are indented one level deeper than the functions they belong to.
+			buf.AppendString(strings.Replace(line, "\t", consoleStackIndent, 1))
+		}
+	}
+
+	buf.AppendString(zapcore.DefaultLineEnding)
+
+	return buf, nil
+}
+
+func (e *consoleEncoder) appendContext(buf *buffer.Buffer) {
+	labelKeys, fieldKeys := splitContextKeys(e.Fields)
+
+	if len(labelKeys) > 0 {
+		buf.AppendString(" [")
+
+		for i, key := range labelKeys {
+			if i > 0 {
+				buf.AppendByte(' ')
+			}
+
+			e.appendKeyValue(buf, key)
+		}
+
+		buf.AppendByte(']')
+	}
+
+	for _, key := range fieldKeys {
+		buf.AppendByte(' ')
+		e.appendKeyValue(buf, key)
+	}
+}
+
+// splitContextKeys returns sorted keys of labels, as listed by the tags field, and sorted keys of other fields.
+// The tags field itself is in neither.
+func splitContextKeys(context map[string]interface{}) ([]string, []string) {
+	labels := map[string]bool{}
+
+	if tags, ok := context[tagsKey].([]interface{}); ok {
+		for _, tag := range tags {
+			if key, ok := tag.(string); ok {
+				labels[key] = true
+			}
+		}
+	}
+
+	var labelKeys, fieldKeys []string
+
+	for key := range context {
+		switch {
+		case key == tagsKey:
+		case labels[key]:
+			labelKeys = append(labelKeys, key)
+		default:
+			fieldKeys = append(fieldKeys, key)
+		}
+	}
+
+	sort.Strings(labelKeys)
+	sort.Strings(fieldKeys)
+
+	return labelKeys, fieldKeys
+}
+
+func (e *consoleEncoder) appendKeyValue(buf *buffer.Buffer, key string) {
+	e.colored(buf, colorGray, key+"=")
+	buf.AppendString(formatConsoleValue(e.Fields[key]))
+}
+
+func (e *consoleEncoder) colored(buf *buffer.Buffer, color, s string) {
+	if !e.color {
+		buf.AppendString(s)
+
+		return
+	}
+
+	buf.AppendString(color)
+	buf.AppendString(s)
+	buf.AppendString(colorReset)
+}
+
+func formatConsoleValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, " =\"\t\n") {
+			return strconv.Quote(v)
+		}
+
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case []interface{}, map[string]interface{}:
+		out, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+
+		return string(out)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func levelName(lvl zapcore.Level) string {
+	if lvl == traceLevel {
+		return "trace"
+	}
+
+	return lvl.String()
+}
+
+func levelColor(lvl zapcore.Level) string {
+	switch {
+	case lvl <= traceLevel:
+		return colorGray
+	case lvl == zapcore.DebugLevel:
+		return colorMagenta
+	case lvl == zapcore.InfoLevel:
+		return colorBlue
+	case lvl == zapcore.WarnLevel:
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"context"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+// ContextExtractor turns values carried by a context into fields.
+// It returns nil or empty Fields if the context carries nothing of interest.
+type ContextExtractor func(ctx context.Context) logger.Fields
+
+// WithContextExtractors returns Option that registers extractors used by WithContext.
+// Extractors run in the order they were registered, so later ones overwrite fields of earlier ones.
+func WithContextExtractors(extractors ...ContextExtractor) Option {
+	return func(ls *loggerSettings) {
+		ls.extractors = append(ls.extractors[:len(ls.extractors):len(ls.extractors)], extractors...)
+	}
+}
+
+// ContextValue returns ContextExtractor that adds the value stored under key as field, if present.
+func ContextValue(key interface{}, field string) ContextExtractor {
+	return func(ctx context.Context) logger.Fields {
+		val := ctx.Value(key)
+		if val == nil {
+			return nil
+		}
+
+		return logger.Fields{field: val}
+	}
+}
+
+// WithContext returns a child logger with the fields produced by the registered extractors added to every entry.
+func (l *StandardLog) WithContext(ctx context.Context) logger.Log {
+	fields := logger.Fields{}
+
+	for _, extract := range l.extractors {
+		for key, val := range extract(ctx) {
+			fields[key] = val
+		}
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.With(fields)
+}
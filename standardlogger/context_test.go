@@ -0,0 +1,109 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+type requestIDKey struct{}
+
+type tenantIDKey struct{}
+
+func TestStandardLog_WithContext(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(
+		logger.Labels{"product": "Persistor"},
+		standardlogger.WithContextExtractors(
+			standardlogger.ContextValue(requestIDKey{}, "requestId"),
+			standardlogger.ContextValue(tenantIDKey{}, "tenantId"),
+		),
+	)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	ctx = context.WithValue(ctx, tenantIDKey{}, "tenant-1")
+
+	ctxLog := log.WithContext(ctx)
+	ctxLog.Info("first")
+	ctxLog.Errorw("second", 1000, logger.F{})
+
+	for _, entry := range logs.All() {
+		for _, field := range []zap.Field{
+			zap.String("product", "Persistor"),
+			zap.String("requestId", "req-1"),
+			zap.String("tenantId", "tenant-1"),
+		} {
+			if !standardlogger.HasField(entry, field) {
+				t.Errorf("Field not found in %s, want %v.", entry.Message, field)
+			}
+		}
+	}
+}
+
+func TestStandardLog_WithContextMissingValue(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(
+		logger.Labels{},
+		standardlogger.WithContextExtractors(standardlogger.ContextValue(requestIDKey{}, "requestId")),
+	)
+
+	log.WithContext(context.Background()).Info("msg")
+
+	if _, ok := logs.All()[0].ContextMap()["requestId"]; ok {
+		t.Error("Field present for missing context value.")
+	}
+}
+
+func TestStandardLog_WithContextCustomExtractor(t *testing.T) {
+	extractor := func(ctx context.Context) logger.Fields {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return logger.Fields{"requestId": id, "traced": true}
+		}
+
+		return nil
+	}
+
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithContextExtractors(extractor))
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	log.WithContext(ctx).Info("msg")
+
+	for _, field := range []zap.Field{zap.String("requestId", "req-1"), zap.Bool("traced", true)} {
+		if !standardlogger.HasField(logs.All()[0], field) {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+}
+
+func TestStandardLog_WithContextPropagation(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"product": "Persistor"})
+
+	ctx := logger.NewContext(context.Background(), log.With(logger.F{"reqId": 22}))
+
+	fromCtx, ok := logger.FromContext(ctx)
+	if !ok {
+		t.Fatal("Log not found in context.")
+	}
+
+	fromCtx.Info("msg")
+
+	if !standardlogger.HasField(logs.All()[0], zap.Int("reqId", 22)) {
+		t.Error("Field of the propagated logger missing.")
+	}
+}
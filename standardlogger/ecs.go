@@ -0,0 +1,68 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+// ecsVersion is the version of the Elastic Common Schema the entries conform to.
+const ecsVersion = "8.11.0"
+
+// ecsSchema follows the Elastic Common Schema, see https://www.elastic.co/guide/en/ecs/current/index.html.
+// Dotted keys such as log.level are expanded into objects by Elasticsearch.
+// The error object of ErrorE already uses the keys of the ECS error fields: error.message, error.type and error.stack_trace.
+var ecsSchema = &schema{
+	codeField: func(code uint64) zap.Field {
+		// error.code is a keyword in ECS.
+		return zap.String("error.code", strconv.FormatUint(code, 10))
+	},
+	labelFields: func(labels logger.Labels) []zap.Field {
+		return []zap.Field{
+			zap.String("ecs.version", ecsVersion),
+			zap.Object("labels", labelsObject(labels)),
+			zap.Strings(tagsKey, GetLabelsKeys(labels)),
+		}
+	},
+	encoderConfig: func(conf *zapcore.EncoderConfig) {
+		conf.TimeKey = "@timestamp"
+		conf.LevelKey = "log.level"
+		conf.MessageKey = "message"
+		conf.NameKey = "log.logger"
+		conf.StacktraceKey = "error.stack_trace"
+		conf.FunctionKey = "log.origin.function"
+		// the caller is split into log.origin.file.name and log.origin.file.line by encodeEntry.
+		conf.CallerKey = zapcore.OmitKey
+	},
+	encodeEntry: func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		if !ent.Caller.Defined {
+			return ent, fields
+		}
+
+		return ent, prependFields([]zap.Field{
+			zap.String("log.origin.file.name", callerFile(ent.Caller)),
+			zap.Int("log.origin.file.line", ent.Caller.Line),
+		}, fields)
+	},
+	reservedKeys: []string{
+		"@timestamp", "log.level", "message", "log.logger", "error.stack_trace", "log.origin.function",
+		"log.origin.file.name", "log.origin.file.line", "ecs.version", "labels", tagsKey, "error.code",
+	},
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func logProfileEntry(t *testing.T, profile standardlogger.Profile, log func(logger.Log)) map[string]interface{} {
+	t.Helper()
+
+	var output bytes.Buffer
+
+	log(standardlogger.New(logger.Labels{"product": "Persistor"},
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithProfile(profile),
+		standardlogger.WithSingleOutput(&output),
+	))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Fatalf("Output %q is not JSON: %v.", output.String(), err)
+	}
+
+	return entry
+}
+
+func TestWithProfile_ECS(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileECS, func(log logger.Log) {
+		log.Errorw("Error msg", 1000, logger.F{"reqId": 22})
+	})
+
+	for key, expected := range map[string]interface{}{
+		"log.level":            "error",
+		"message":              "Error msg",
+		"error.code":           "1000",
+		"log.origin.file.name": "standardlogger/ecs_test.go",
+		"reqId":                float64(22),
+		"ecs.version":          "8.11.0",
+	} {
+		if entry[key] != expected {
+			t.Errorf("Wrong %s %v, want %v.", key, entry[key], expected)
+		}
+	}
+
+	for _, key := range []string{"@timestamp", "log.origin.file.line", "log.origin.function", "error.stack_trace"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("Key %s missing.", key)
+		}
+	}
+
+	for _, key := range []string{"ts", "level", "msg", "caller", "stacktrace", "code", "product"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Default key %s present.", key)
+		}
+	}
+
+	labels, ok := entry["labels"].(map[string]interface{})
+	if !ok || labels["product"] != "Persistor" {
+		t.Errorf("Wrong labels %v, want product nested under labels.", entry["labels"])
+	}
+
+	tags, ok := entry["tags"].([]interface{})
+	if !ok || len(tags) != 1 || tags[0] != "product" {
+		t.Errorf("Wrong tags %v.", entry["tags"])
+	}
+}
+
+func TestWithProfile_ECSWithLabels(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileECS, func(log logger.Log) {
+		log.WithLabels(logger.Labels{"component": "routine"}).Info("Info msg")
+	})
+
+	labels, ok := entry["labels"].(map[string]interface{})
+	if !ok || labels["product"] != "Persistor" || labels["component"] != "routine" {
+		t.Errorf("Wrong labels %v.", entry["labels"])
+	}
+}
+
+func TestWithProfile_Default(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileDefault, func(log logger.Log) {
+		log.Error("Error msg", 1000)
+	})
+
+	for key, expected := range map[string]interface{}{
+		"level":   "error",
+		"msg":     "Error msg",
+		"code":    float64(1000),
+		"product": "Persistor",
+	} {
+		if entry[key] != expected {
+			t.Errorf("Wrong %s %v, want %v.", key, entry[key], expected)
+		}
+	}
+}
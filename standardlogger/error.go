@@ -0,0 +1,192 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const (
+	errorKey = "error"
+	// maxErrorChain stops walking the chain of errors that wrap themselves.
+	maxErrorChain = 32
+)
+
+// stackTracer is implemented by errors of github.com/pkg/errors that record where they were created.
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// multiUnwrapper is implemented by errors that wrap several errors, such as the ones returned by errors.Join.
+type multiUnwrapper interface {
+	Unwrap() []error
+}
+
+// ErrorE logs err as an error object with its message, type, causes and the stack trace of where it was created:
+//
+//	"error": {"message": "...", "type": "*fs.PathError", "chain": [{"message": "...", "type": "..."}], "stack_trace": "..."}
+//
+// The chain lists err and all errors it wraps, following both Unwrap() error and Unwrap() []error.
+// The stack trace is taken from the innermost error created by github.com/pkg/errors and replaces the stack trace
+// of the entry. Profiles may place the error and its stack trace differently, e.g. ProfileGCP keeps the stack trace
+// at the top level for Error Reporting.
+func (l *StandardLog) ErrorE(msg string, code uint64, err error, fields logger.Fields) {
+	zapFields := l.codeFields(code)
+	entryKeys := fieldKeys(zapFields)
+
+	if err != nil {
+		entryKeys = append(entryKeys, errorKey)
+	}
+
+	zapFields = append(zapFields, l.zapFields(fields, entryKeys)...)
+
+	if err != nil {
+		zapFields = append(zapFields, zap.Object(errorKey, newErrorObject(err, l.redactor.message)))
+	}
+
+	l.checkErrorCode(code)
+
+	log, zapFields := l.errorLogger(zapFields)
+	log.Log(l.errorLevel(code), l.redactor.message(msg), zapFields...)
+}
+
+// errorLogger returns the logger for an entry with fields. If the error object among fields recorded where
+// the error was created, the returned logger doesn't add the stack trace of the call site,
+// so the entry has a single stack trace. Profiles may move the error's stack trace when encoding the entry.
+func (l *StandardLog) errorLogger(fields []zap.Field) (zapLogger, []zap.Field) {
+	for _, field := range fields {
+		if obj, ok := field.Interface.(errorObject); ok && field.Key == errorKey && obj.stack != "" {
+			return l.withOptions(zap.AddStacktrace(zap.LevelEnablerFunc(func(zapcore.Level) bool { return false }))), fields
+		}
+	}
+
+	return l.ZapLogger, fields
+}
+
+// errorObject marshals an error with its chain and stack trace.
+type errorObject struct {
+	err    error
+	chain  []error
+	stack  string
+	redact func(string) string
+}
+
+func newErrorObject(err error, redact func(string) string) errorObject {
+	chain := errorChain(err)
+
+	return errorObject{
+		err:    err,
+		chain:  chain,
+		stack:  originStackTrace(chain),
+		redact: redact,
+	}
+}
+
+// withoutStack returns the error object without the stack trace, for schemas that place it elsewhere.
+func (e errorObject) withoutStack() errorObject {
+	e.stack = ""
+
+	return e
+}
+
+func (e errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", e.redact(e.err.Error()))
+	enc.AddString("type", errorType(e.err))
+
+	if err := enc.AddArray("chain", errorChainArray{chain: e.chain, redact: e.redact}); err != nil {
+		return err //nolint:wrapcheck //encoder errors are handled by zap
+	}
+
+	if e.stack != "" {
+		enc.AddString("stack_trace", e.stack)
+	}
+
+	return nil
+}
+
+type errorChainArray struct {
+	chain  []error
+	redact func(string) string
+}
+
+func (a errorChainArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range a.chain {
+		err := err
+
+		if marshalErr := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("message", a.redact(err.Error()))
+			enc.AddString("type", errorType(err))
+
+			return nil
+		})); marshalErr != nil {
+			return marshalErr //nolint:wrapcheck //encoder errors are handled by zap
+		}
+	}
+
+	return nil
+}
+
+// errorChain returns err and the errors it wraps, depth first.
+func errorChain(err error) []error {
+	var chain []error
+
+	var walk func(err error)
+
+	walk = func(err error) {
+		if err == nil || len(chain) >= maxErrorChain {
+			return
+		}
+
+		chain = append(chain, err)
+
+		switch wrapper := err.(type) { //nolint:errorlint //unwrapping manually to walk the whole chain
+		case multiUnwrapper:
+			for _, wrapped := range wrapper.Unwrap() {
+				walk(wrapped)
+			}
+		case interface{ Unwrap() error }:
+			walk(wrapper.Unwrap())
+		case interface{ Cause() error }:
+			// github.com/pkg/errors before Unwrap was added.
+			walk(wrapper.Cause())
+		}
+	}
+
+	walk(err)
+
+	return chain
+}
+
+func errorType(err error) string {
+	return fmt.Sprintf("%T", err)
+}
+
+// originStackTrace returns the stack trace of the innermost error in the chain that recorded one.
+func originStackTrace(chain []error) string {
+	for i := len(chain) - 1; i >= 0; i-- {
+		if tracer, ok := chain[i].(stackTracer); ok { //nolint:errorlint //the chain is already unwrapped
+			return strings.TrimPrefix(fmt.Sprintf("%+v", tracer.StackTrace()), "\n")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,248 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+// joinedError wraps several errors, as the ones returned by errors.Join.
+type joinedError []error
+
+func (e joinedError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e joinedError) Unwrap() []error {
+	return e
+}
+
+// logErrorObject logs err with ErrorE and returns the error object of the entry.
+func logErrorObject(t *testing.T, err error, opts ...standardlogger.Option) map[string]interface{} {
+	t.Helper()
+
+	log, logs := standardlogger.NewForTesting(nil, opts...)
+	log.ErrorE("Error msg", 1000, err, logger.F{"id": "1"})
+
+	entry := logs.All()[0]
+	if entry.Message != "Error msg" || entry.ContextMap()["id"] != "1" || entry.ContextMap()["code"] != uint64(1000) {
+		t.Errorf("Wrong entry %s %v.", entry.Message, entry.ContextMap())
+	}
+
+	object, ok := entry.ContextMap()["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Error object missing from %v.", entry.ContextMap())
+	}
+
+	return object
+}
+
+func chainTypes(object map[string]interface{}) []string {
+	chain, _ := object["chain"].([]interface{})
+
+	types := make([]string, 0, len(chain))
+	for _, cause := range chain {
+		cause, _ := cause.(map[string]interface{})
+		types = append(types, fmt.Sprint(cause["type"]))
+	}
+
+	return types
+}
+
+func TestStandardLog_ErrorE(t *testing.T) {
+	err := fmt.Errorf("reading config: %w", io.ErrUnexpectedEOF)
+
+	object := logErrorObject(t, err)
+
+	if object["message"] != "reading config: unexpected EOF" || object["type"] != "*fmt.wrapError" {
+		t.Errorf("Wrong error %v.", object)
+	}
+
+	if types := chainTypes(object); strings.Join(types, ",") != "*fmt.wrapError,*errors.errorString" {
+		t.Errorf("Wrong chain %v.", types)
+	}
+
+	chain, _ := object["chain"].([]interface{})
+	if cause, _ := chain[1].(map[string]interface{}); cause["message"] != "unexpected EOF" {
+		t.Errorf("Wrong cause %v.", chain[1])
+	}
+
+	if _, ok := object["stack_trace"]; ok {
+		t.Error("Stack trace of an error without one.")
+	}
+}
+
+func TestStandardLog_ErrorEStackTrace(t *testing.T) {
+	err := errors.Wrap(errors.New("connection refused"), "connecting to broker")
+
+	object := logErrorObject(t, err)
+
+	stack, _ := object["stack_trace"].(string)
+	if !strings.Contains(stack, "TestStandardLog_ErrorEStackTrace") || !strings.Contains(stack, "error_test.go") {
+		t.Errorf("Wrong stack trace %q.", stack)
+	}
+
+	types := chainTypes(object)
+	if len(types) != 3 || types[2] != "*errors.fundamental" {
+		t.Errorf("Wrong chain %v.", types)
+	}
+}
+
+func TestStandardLog_ErrorEJoined(t *testing.T) {
+	err := joinedError{io.EOF, fmt.Errorf("closing: %w", io.ErrClosedPipe)}
+
+	object := logErrorObject(t, err)
+
+	expected := "standardlogger_test.joinedError,*errors.errorString,*fmt.wrapError,*errors.errorString"
+	if types := chainTypes(object); strings.Join(types, ",") != expected {
+		t.Errorf("Wrong chain %v, want %s.", types, expected)
+	}
+}
+
+func TestStandardLog_ErrorENil(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil)
+	log.ErrorE("Error msg", 1000, nil, nil)
+
+	if _, ok := logs.All()[0].ContextMap()["error"]; ok {
+		t.Error("Error object of a nil error.")
+	}
+}
+
+func TestStandardLog_ErrorERedaction(t *testing.T) {
+	err := fmt.Errorf("user %s: %w", "ann@example.com", io.EOF)
+
+	object := logErrorObject(t, err, standardlogger.WithRedaction(standardlogger.RedactionRule{
+		ValuePatterns: []*regexp.Regexp{standardlogger.EmailPattern},
+		Strategy:      standardlogger.RedactMask,
+	}))
+
+	if object["message"] != "user [REDACTED]: EOF" {
+		t.Errorf("Wrong message %v.", object["message"])
+	}
+}
+
+// newOriginError returns an error that records this function as where it was created.
+func newOriginError() error {
+	return errors.New("connection refused")
+}
+
+// logErrorEntry logs err with ErrorE as JSON with profile and returns the decoded entry.
+func logErrorEntry(t *testing.T, profile standardlogger.Profile, err error) map[string]interface{} {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithProfile(profile),
+	)
+	log.ErrorE("Error msg", 1000, err, nil)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Decoding %s failed: %v", buf.String(), err)
+	}
+
+	return entry
+}
+
+func TestStandardLog_ErrorESingleStackTrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile standardlogger.Profile
+		// stack returns the stack trace of the entry and the number of stack traces it has.
+		stack func(entry map[string]interface{}) (string, int)
+	}{
+		{"default", standardlogger.ProfileDefault, func(entry map[string]interface{}) (string, int) {
+			return stackTraces(entry, "stacktrace", errorStackTrace(entry))
+		}},
+		{"ecs", standardlogger.ProfileECS, func(entry map[string]interface{}) (string, int) {
+			return stackTraces(entry, "error.stack_trace", errorStackTrace(entry))
+		}},
+		{"gcp", standardlogger.ProfileGCP, func(entry map[string]interface{}) (string, int) {
+			return stackTraces(entry, "stack_trace", errorStackTrace(entry))
+		}},
+		{"azure", standardlogger.ProfileAzure, func(entry map[string]interface{}) (string, int) {
+			exceptions, _ := entry["exceptions"].([]interface{})
+			exception, _ := exceptions[0].(map[string]interface{})
+			stack, _ := exception["stack"].(string)
+
+			return stackTraces(entry, "stacktrace", errorStackTrace(entry), stack)
+		}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			entry := logErrorEntry(t, test.profile, newOriginError())
+
+			stack, count := test.stack(entry)
+			if count != 1 || !strings.Contains(stack, "newOriginError") {
+				t.Errorf("Expected the error's stack trace only, got %d stack traces in %v.", count, entry)
+			}
+
+			entry = logErrorEntry(t, test.profile, io.EOF)
+
+			stack, count = test.stack(entry)
+			if count != 1 || !strings.Contains(stack, "TestStandardLog_ErrorESingleStackTrace") {
+				t.Errorf("Expected the call site's stack trace only, got %d stack traces in %v.", count, entry)
+			}
+		})
+	}
+}
+
+func errorStackTrace(entry map[string]interface{}) string {
+	object, _ := entry["error"].(map[string]interface{})
+	stack, _ := object["stack_trace"].(string)
+
+	return stack
+}
+
+// stackTraces returns the non-empty stack traces among the value of key in entry and others, and their number.
+func stackTraces(entry map[string]interface{}, key string, others ...string) (string, int) {
+	top, _ := entry[key].(string)
+
+	var (
+		stack string
+		count int
+	)
+
+	for _, s := range append([]string{top}, others...) {
+		if s != "" {
+			stack = s
+			count++
+		}
+	}
+
+	return stack, count
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const (
+	codeNameKey = "code_name"
+	categoryKey = "category"
+)
+
+// WithErrorCodeRegistry returns Option that adds the name and category of error codes passed to Error, Fatal
+// and Panic as code_name and category, if they are registered in registry. Error entries are logged at the severity
+// of their code, if it is lower than LevelError. The first time an unregistered code is used, a warning is logged,
+// as well as for every code the registry rejected as a duplicate when the logger is created. Code 0 is treated as no code at all.
+func WithErrorCodeRegistry(registry *logger.ErrorCodeRegistry) Option {
+	return func(ls *loggerSettings) {
+		ls.errorCodeRegistry = registry
+	}
+}
+
+// errorCodes is shared by a root logger and its children, so unregistered codes are reported only once.
+type errorCodes struct {
+	registry *logger.ErrorCodeRegistry
+	warned   sync.Map
+}
+
+func newErrorCodes(registry *logger.ErrorCodeRegistry) *errorCodes {
+	if registry == nil {
+		return nil
+	}
+
+	return &errorCodes{registry: registry}
+}
+
+// codeFields returns the error code field, followed by the name and category of registered codes.
+func (l *StandardLog) codeFields(code uint64) []zap.Field {
+	fields := []zap.Field{l.getSchema().codeField(code)}
+
+	if l.errorCodes == nil || code == 0 {
+		return fields
+	}
+
+	errorCode, ok := l.errorCodes.registry.Lookup(code)
+	if !ok {
+		return fields
+	}
+
+	fields = append(fields, zap.String(codeNameKey, errorCode.Name))
+
+	if errorCode.Category != "" {
+		fields = append(fields, zap.String(categoryKey, errorCode.Category))
+	}
+
+	return fields
+}
+
+// checkErrorCode logs a warning the first time an unregistered code is used.
+// The logging methods call it directly, so the warning reports their caller.
+func (l *StandardLog) checkErrorCode(code uint64) {
+	if l.errorCodes == nil || code == 0 {
+		return
+	}
+
+	if _, ok := l.errorCodes.registry.Lookup(code); ok {
+		return
+	}
+
+	if _, warned := l.errorCodes.warned.LoadOrStore(code, struct{}{}); !warned {
+		l.withOptions(zap.AddCallerSkip(1)).Warn("Unregistered error code.", l.getSchema().codeField(code))
+	}
+}
+
+// errorLevel returns the level of Error entries with code,
+// which is the severity of the registered code if it is lower than LevelError.
+func (l *StandardLog) errorLevel(code uint64) zapcore.Level {
+	if l.errorCodes == nil || code == 0 {
+		return zapcore.ErrorLevel
+	}
+
+	errorCode, ok := l.errorCodes.registry.Lookup(code)
+	if !ok || errorCode.Severity == nil || *errorCode.Severity >= logger.LevelError {
+		return zapcore.ErrorLevel
+	}
+
+	return getLevelAsZapLevel(*errorCode.Severity)
+}
+
+// warnDuplicateErrorCodes logs a warning for every code the registry rejected as a duplicate.
+// It is called by the constructors, so the warnings report their caller.
+func (l *StandardLog) warnDuplicateErrorCodes() {
+	if l.errorCodes == nil {
+		return
+	}
+
+	for _, duplicate := range l.errorCodes.registry.Duplicates() {
+		registered, _ := l.errorCodes.registry.Lookup(duplicate.Code)
+
+		l.withOptions(zap.AddCallerSkip(2)).Warn("Duplicate error code.", //nolint:gomnd //newStandardLog and the constructor
+			l.getSchema().codeField(duplicate.Code),
+			zap.String(codeNameKey, duplicate.Name),
+			zap.String("registered_as", registered.Name),
+		)
+	}
+}
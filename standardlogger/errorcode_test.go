@@ -0,0 +1,170 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func newErrorCodeRegistry() *logger.ErrorCodeRegistry {
+	registry := logger.NewErrorCodeRegistry()
+	registry.Register(logger.ErrorCode{
+		Code:     1001,
+		Name:     "BrokerUnreachable",
+		Severity: logger.Severity(logger.LevelError),
+		Category: "connectivity",
+	})
+
+	return registry
+}
+
+func TestWithErrorCodeRegistry(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithErrorCodeRegistry(newErrorCodeRegistry()))
+
+	log.Errorw("Error msg", 1001, logger.F{"id": "1"})
+
+	entry := logs.All()[0]
+
+	for _, field := range []zap.Field{
+		zap.Uint64("code", 1001),
+		zap.String("code_name", "BrokerUnreachable"),
+		zap.String("category", "connectivity"),
+		zap.String("id", "1"),
+	} {
+		if !standardlogger.HasField(entry, field) {
+			t.Errorf("Field %s missing from %v.", field.Key, entry.ContextMap())
+		}
+	}
+}
+
+func TestWithErrorCodeRegistry_Unregistered(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithErrorCodeRegistry(newErrorCodeRegistry()))
+
+	log.Error("Error msg", 2000)
+	log.WithLabels(logger.Labels{"component": "writer"}).Error("Error msg", 2000)
+	log.Error("Error msg", 0)
+
+	warnings := standardlogger.FindByMessage(logs, "Unregistered error code.")
+	if len(warnings) != 1 || !standardlogger.HasField(warnings[0], zap.Uint64("code", 2000)) {
+		t.Errorf("Expected a single warning about code 2000, got %v.", warnings)
+	}
+
+	for _, entry := range standardlogger.FindByMessage(logs, "Error msg") {
+		if _, ok := entry.ContextMap()["code_name"]; ok {
+			t.Errorf("Unregistered code has a name: %v.", entry.ContextMap())
+		}
+	}
+}
+
+func TestWithoutErrorCodeRegistry(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil)
+
+	log.Error("Error msg", 1001)
+
+	if logs.Len() != 1 {
+		t.Errorf("Expected only the error, got %d entries.", logs.Len())
+	}
+
+	if _, ok := logs.All()[0].ContextMap()["code_name"]; ok {
+		t.Error("Code name added without a registry.")
+	}
+}
+
+func TestWithErrorCodeRegistry_UnregisteredCaller(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithErrorCodeRegistry(newErrorCodeRegistry()))
+
+	log.Errorw("Error msg", 2000, nil)
+	log.ErrorE("Error msg", 2001, io.EOF, nil)
+	log.ErrorWith("Error msg", 2002)
+
+	for _, warning := range standardlogger.FindByMessage(logs, "Unregistered error code.") {
+		if !strings.HasSuffix(warning.Caller.File, "errorcode_test.go") {
+			t.Errorf("Warning reports caller %s.", warning.Caller.File)
+		}
+	}
+
+	if n := len(standardlogger.FindByMessage(logs, "Unregistered error code.")); n != 3 {
+		t.Errorf("Expected 3 warnings, got %d.", n)
+	}
+}
+
+func TestWithErrorCodeRegistry_Duplicates(t *testing.T) {
+	registry := newErrorCodeRegistry()
+	registry.Register(logger.ErrorCode{Code: 1001, Name: "BrokerDown"})
+
+	_, logs := standardlogger.NewForTesting(nil, standardlogger.WithErrorCodeRegistry(registry))
+
+	warnings := standardlogger.FindByMessage(logs, "Duplicate error code.")
+	if len(warnings) != 1 {
+		t.Fatalf("Expected a single warning, got %v.", warnings)
+	}
+
+	for _, field := range []zap.Field{
+		zap.Uint64("code", 1001),
+		zap.String("code_name", "BrokerDown"),
+		zap.String("registered_as", "BrokerUnreachable"),
+	} {
+		if !standardlogger.HasField(warnings[0], field) {
+			t.Errorf("Field %s missing from %v.", field.Key, warnings[0].ContextMap())
+		}
+	}
+
+	if !strings.HasSuffix(warnings[0].Caller.File, "errorcode_test.go") {
+		t.Errorf("Warning reports caller %s.", warnings[0].Caller.File)
+	}
+}
+
+func TestWithErrorCodeRegistry_Severity(t *testing.T) {
+	registry := newErrorCodeRegistry()
+	registry.Register(
+		logger.ErrorCode{Code: 1002, Name: "RetryableTimeout", Severity: logger.Severity(logger.LevelWarn)},
+		logger.ErrorCode{Code: 1003, Name: "Corrupted", Severity: logger.Severity(logger.LevelFatal)},
+		logger.ErrorCode{Code: 1004, Name: "Retrying", Severity: logger.Severity(logger.LevelInfo)},
+		logger.ErrorCode{Code: 1005, Name: "Unclassified"},
+	)
+
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithErrorCodeRegistry(registry))
+
+	log.Error("Timeout", 1002)
+	log.ErrorE("Timeout", 1002, io.EOF, nil)
+	log.Errorw("Corrupted", 1003, nil)
+	log.ErrorWith("Unreachable", 1001)
+	log.Error("Retrying", 1004)
+	log.Error("Unclassified", 1005)
+
+	for _, entry := range standardlogger.FindByMessage(logs, "Timeout") {
+		if entry.Level != zapcore.WarnLevel {
+			t.Errorf("Expected warn level, got %s.", entry.Level)
+		}
+	}
+
+	if entry := standardlogger.FindByMessage(logs, "Retrying")[0]; entry.Level != zapcore.InfoLevel {
+		t.Errorf("Expected info level, got %s.", entry.Level)
+	}
+
+	for _, msg := range []string{"Corrupted", "Unreachable", "Unclassified"} {
+		if entry := standardlogger.FindByMessage(logs, msg)[0]; entry.Level != zapcore.ErrorLevel {
+			t.Errorf("Expected error level, got %s.", entry.Level)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultShutdownTimeout = 5 * time.Second
+	defaultExitCode        = 1
+	// exit codes above 125 have special meaning in shells, e.g. 128+n for termination by signal n.
+	maxExitCode = 125
+)
+
+// ShutdownHook releases a resource before the process exits on Fatal.
+// It should return once ctx is done.
+type ShutdownHook func(ctx context.Context) error
+
+// WithExitFunc returns Option that replaces os.Exit, which Fatal and Fatalw call after logging the entry,
+// running the shutdown hooks and closing the logger. This is useful in tests.
+func WithExitFunc(exit func(code int)) Option {
+	return func(ls *loggerSettings) {
+		ls.exitFunc = exit
+	}
+}
+
+// WithExitCode returns Option that sets how the exit code is derived from the error code passed to Fatal.
+// By default, error codes from 1 to 125 are used as the exit code and other error codes exit with 1.
+func WithExitCode(exitCode func(code uint64) int) Option {
+	return func(ls *loggerSettings) {
+		ls.exitCode = exitCode
+	}
+}
+
+// WithShutdownHooks returns Option that registers hooks run by Fatal before exiting.
+func WithShutdownHooks(hooks ...ShutdownHook) Option {
+	return func(ls *loggerSettings) {
+		ls.shutdownHooks = append(ls.shutdownHooks, hooks...)
+	}
+}
+
+// WithShutdownTimeout returns Option that limits how long Fatal waits for the shutdown hooks, 5 seconds by default.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(ls *loggerSettings) {
+		ls.shutdownTimeout = timeout
+	}
+}
+
+// defaultExitCodeFunc uses error codes that are valid exit codes as-is.
+func defaultExitCodeFunc(code uint64) int {
+	if code == 0 || code > maxExitCode {
+		return defaultExitCode
+	}
+
+	return int(code)
+}
+
+// shutdown is shared by a root logger and its children and holds what Fatal does after logging.
+type shutdown struct {
+	mu      sync.Mutex
+	hooks   []ShutdownHook
+	timeout time.Duration
+	exit    func(code int)
+	code    func(code uint64) int
+	once    sync.Once
+}
+
+func newShutdown(settings loggerSettings) *shutdown {
+	s := &shutdown{
+		hooks:   append([]ShutdownHook(nil), settings.shutdownHooks...),
+		timeout: settings.shutdownTimeout,
+		exit:    settings.exitFunc,
+		code:    settings.exitCode,
+	}
+
+	if s.timeout <= 0 {
+		s.timeout = defaultShutdownTimeout
+	}
+
+	if s.exit == nil {
+		s.exit = os.Exit
+	}
+
+	if s.code == nil {
+		s.code = defaultExitCodeFunc
+	}
+
+	return s
+}
+
+// AddShutdownHook registers a hook run by Fatal before exiting, e.g. to close a connection created after the logger.
+// Hooks run in reverse order of registration, like deferred calls.
+func (l *StandardLog) AddShutdownHook(hook ShutdownHook) {
+	if l.shutdown == nil {
+		return
+	}
+
+	l.shutdown.mu.Lock()
+	defer l.shutdown.mu.Unlock()
+
+	l.shutdown.hooks = append(l.shutdown.hooks, hook)
+}
+
+// exit runs the shutdown hooks, closes the logger and exits with the exit code derived from code.
+// Loggers not created by New or NewForTesting leave exiting to their ZapLogger.
+func (l *StandardLog) exit(code uint64) {
+	if l.shutdown == nil {
+		return
+	}
+
+	l.shutdown.once.Do(func() {
+		l.runShutdownHooks()
+		l.Close() //nolint:errcheck,gosec //nowhere to report errors at this point
+	})
+
+	l.shutdown.exit(l.shutdown.code(code))
+}
+
+// runShutdownHooks runs the hooks in reverse order and logs their errors, giving up once the timeout passes.
+func (l *StandardLog) runShutdownHooks() {
+	l.shutdown.mu.Lock()
+	hooks := append([]ShutdownHook(nil), l.shutdown.hooks...)
+	l.shutdown.mu.Unlock()
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdown.timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i](ctx); err != nil {
+				l.ZapLogger.Warn("Shutdown hook failed.", zap.Error(err))
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		l.ZapLogger.Warn("Shutdown hooks timed out.", zap.Duration("timeout", l.shutdown.timeout))
+	}
+}
+
+// noopFatalHook keeps zap from exiting on fatal entries, so StandardLog can shut down first.
+type noopFatalHook struct{}
+
+func (noopFatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {}
@@ -0,0 +1,173 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+var errShutdown = errors.New("connection already closed")
+
+// exitRecorder is an exit func that keeps the exit codes it was called with.
+type exitRecorder struct {
+	codes []int
+}
+
+func (r *exitRecorder) exit(code int) {
+	r.codes = append(r.codes, code)
+}
+
+func TestStandardLog_FatalwExits(t *testing.T) {
+	tests := []struct {
+		code     uint64
+		expected int
+	}{
+		{0, 1},
+		{42, 42},
+		{125, 125},
+		{1000, 1},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+
+		recorder := &exitRecorder{}
+
+		log := standardlogger.New(nil,
+			standardlogger.WithSingleOutput(&buf),
+			standardlogger.WithExitFunc(recorder.exit),
+		)
+
+		log.Fatalw("Fatal msg", test.code, logger.F{"id": 1})
+
+		if len(recorder.codes) != 1 || recorder.codes[0] != test.expected {
+			t.Errorf("Code %d exited with %v, want %d.", test.code, recorder.codes, test.expected)
+		}
+
+		if !strings.Contains(buf.String(), "Fatal msg") {
+			t.Errorf("Fatal entry missing from %s", buf.String())
+		}
+	}
+}
+
+func TestWithExitCode(t *testing.T) {
+	recorder := &exitRecorder{}
+
+	log, logs := standardlogger.NewForTesting(nil,
+		standardlogger.WithExitFunc(recorder.exit),
+		standardlogger.WithExitCode(func(code uint64) int {
+			return int(code / 100)
+		}),
+	)
+
+	log.Fatal("Fatal msg", 300)
+
+	if len(recorder.codes) != 1 || recorder.codes[0] != 3 {
+		t.Errorf("Wrong exit codes %v, want [3].", recorder.codes)
+	}
+
+	if len(standardlogger.FindByMessage(logs, "Fatal msg")) != 1 {
+		t.Error("Fatal entry missing.")
+	}
+}
+
+func TestWithShutdownHooks(t *testing.T) {
+	var buf bytes.Buffer
+
+	var order []string
+
+	recorder := &exitRecorder{}
+
+	var log logger.Log
+
+	log = standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithExitFunc(func(code int) {
+			order = append(order, "exit")
+			recorder.exit(code)
+		}),
+		standardlogger.WithShutdownHooks(func(ctx context.Context) error {
+			order = append(order, "first")
+			log.Info("Closing first")
+
+			return nil
+		}),
+	)
+
+	log.(*standardlogger.StandardLog).AddShutdownHook(func(ctx context.Context) error {
+		order = append(order, "second")
+
+		return errShutdown
+	})
+
+	log.Fatal("Fatal msg", 0)
+
+	if strings.Join(order, ",") != "second,first,exit" {
+		t.Errorf("Wrong order %v.", order)
+	}
+
+	for _, expected := range []string{"Closing first", "Shutdown hook failed.", errShutdown.Error()} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("%q missing from %s", expected, buf.String())
+		}
+	}
+
+	// the logger is closed once the hooks ran.
+	log.Info("After exit")
+
+	if strings.Contains(buf.String(), "After exit") {
+		t.Error("Logger not closed before exit.")
+	}
+}
+
+func TestWithShutdownTimeout(t *testing.T) {
+	var buf bytes.Buffer
+
+	recorder := &exitRecorder{}
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithExitFunc(recorder.exit),
+		standardlogger.WithShutdownTimeout(10*time.Millisecond),
+		standardlogger.WithShutdownHooks(func(ctx context.Context) error {
+			time.Sleep(time.Second)
+
+			return nil
+		}),
+	)
+
+	start := time.Now()
+	log.Fatal("Fatal msg", 0)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Fatal waited %s for a hook past the timeout.", elapsed)
+	}
+
+	if len(recorder.codes) != 1 {
+		t.Errorf("Expected a single exit, got %v.", recorder.codes)
+	}
+
+	if !strings.Contains(buf.String(), "Shutdown hooks timed out.") {
+		t.Errorf("Timeout missing from %s", buf.String())
+	}
+}
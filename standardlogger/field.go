@@ -0,0 +1,209 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+func (l *StandardLog) TraceWith(msg string, fields ...logger.Field) {
+	l.ZapLogger.Log(traceLevel, l.redactor.message(msg), l.typedZapFields(fields, nil)...)
+}
+
+func (l *StandardLog) DebugWith(msg string, fields ...logger.Field) {
+	l.ZapLogger.Debug(l.redactor.message(msg), l.typedZapFields(fields, nil)...)
+}
+
+func (l *StandardLog) InfoWith(msg string, fields ...logger.Field) {
+	l.ZapLogger.Info(l.redactor.message(msg), l.typedZapFields(fields, nil)...)
+}
+
+func (l *StandardLog) WarnWith(msg string, fields ...logger.Field) {
+	l.ZapLogger.Warn(l.redactor.message(msg), l.typedZapFields(fields, nil)...)
+}
+
+// ErrorWith logs the entry. An error added with logger.Err is shaped as in ErrorE.
+func (l *StandardLog) ErrorWith(msg string, code uint64, fields ...logger.Field) {
+	l.checkErrorCode(code)
+
+	log, zapFields := l.errorLogger(l.typedFieldsWithCode(code, fields))
+	log.Log(l.errorLevel(code), l.redactor.message(msg), zapFields...)
+}
+
+// FatalWith logs the entry, runs the shutdown hooks, closes the log and exits with the exit code derived from code.
+func (l *StandardLog) FatalWith(msg string, code uint64, fields ...logger.Field) {
+	l.checkErrorCode(code)
+
+	log, zapFields := l.errorLogger(l.typedFieldsWithCode(code, fields))
+	log.Fatal(l.redactor.message(msg), zapFields...)
+	l.exit(code)
+}
+
+func (l *StandardLog) PanicWith(msg string, code uint64, fields ...logger.Field) {
+	panicData := &PanicContainer{msg: msg, Code: code, typedFields: fields}
+	panic(panicData)
+}
+
+// typedFieldsWithCode returns the error code fields followed by fields.
+func (l *StandardLog) typedFieldsWithCode(code uint64, fields []logger.Field) []zap.Field {
+	codeFields := l.codeFields(code)
+
+	return append(codeFields, l.typedZapFields(fields, fieldKeys(codeFields))...)
+}
+
+// typedZapFields redacts fields and converts them to zap fields. entryKeys are the keys the logger adds to the entry.
+func (l *StandardLog) typedZapFields(fields []logger.Field, entryKeys []string) []zap.Field {
+	return fieldsAsZapFields(l.policyTypedFields(l.redactor.typedFields(fields), entryKeys), l.redactor.message)
+}
+
+// GetFieldsAsZapFields converts typed fields to zap fields, keeping their order.
+// Apart from fields created with logger.Any, values are converted without reflection.
+func GetFieldsAsZapFields(fields []logger.Field) []zap.Field {
+	return fieldsAsZapFields(fields, func(msg string) string { return msg })
+}
+
+func fieldsAsZapFields(fields []logger.Field, redact func(string) string) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	zapFields := make([]zap.Field, 0, len(fields))
+
+	for _, field := range fields {
+		if field.Type == logger.FieldTypeError && field.Interface == nil {
+			continue
+		}
+
+		zapFields = append(zapFields, fieldAsZapField(field, redact))
+	}
+
+	return zapFields
+}
+
+func fieldAsZapField(field logger.Field, redact func(string) string) zap.Field {
+	switch field.Type {
+	case logger.FieldTypeString:
+		return zap.String(field.Key, field.String)
+	case logger.FieldTypeInt:
+		return zap.Int64(field.Key, field.Integer)
+	case logger.FieldTypeUint:
+		return zap.Uint64(field.Key, uint64(field.Integer))
+	case logger.FieldTypeFloat:
+		return zap.Float64(field.Key, math.Float64frombits(uint64(field.Integer)))
+	case logger.FieldTypeBool:
+		return zap.Bool(field.Key, field.Integer == 1)
+	case logger.FieldTypeDuration:
+		return zap.Duration(field.Key, time.Duration(field.Integer))
+	case logger.FieldTypeTime:
+		if t, ok := field.Interface.(time.Time); ok {
+			return zap.Time(field.Key, t)
+		}
+	case logger.FieldTypeError:
+		if err, ok := field.Interface.(error); ok {
+			return zap.Object(field.Key, newErrorObject(err, redact))
+		}
+	case logger.FieldTypeObject:
+		if nested, ok := field.Interface.([]logger.Field); ok {
+			return zap.Object(field.Key, fieldsObject{fields: nested, redact: redact})
+		}
+	case logger.FieldTypeArray:
+		if elems, ok := field.Interface.([]logger.Field); ok {
+			return zap.Array(field.Key, fieldsArray{elems: elems, redact: redact})
+		}
+	case logger.FieldTypeAny:
+	}
+
+	return zap.Any(field.Key, field.Interface)
+}
+
+// fieldsObject marshals typed fields as a nested object.
+type fieldsObject struct {
+	fields []logger.Field
+	redact func(string) string
+}
+
+func (o fieldsObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range fieldsAsZapFields(o.fields, o.redact) {
+		field.AddTo(enc)
+	}
+
+	return nil
+}
+
+// fieldsArray marshals the values of typed fields as an array.
+type fieldsArray struct {
+	elems  []logger.Field
+	redact func(string) string
+}
+
+func (a fieldsArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, elem := range a.elems {
+		if err := appendField(enc, elem, a.redact); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//nolint:wrapcheck //encoder errors are handled by zap
+func appendField(enc zapcore.ArrayEncoder, field logger.Field, redact func(string) string) error {
+	switch field.Type {
+	case logger.FieldTypeString:
+		enc.AppendString(field.String)
+	case logger.FieldTypeInt:
+		enc.AppendInt64(field.Integer)
+	case logger.FieldTypeUint:
+		enc.AppendUint64(uint64(field.Integer))
+	case logger.FieldTypeFloat:
+		enc.AppendFloat64(math.Float64frombits(uint64(field.Integer)))
+	case logger.FieldTypeBool:
+		enc.AppendBool(field.Integer == 1)
+	case logger.FieldTypeDuration:
+		enc.AppendDuration(time.Duration(field.Integer))
+	case logger.FieldTypeObject, logger.FieldTypeArray, logger.FieldTypeError, logger.FieldTypeTime:
+		return appendNestedField(enc, field, redact)
+	case logger.FieldTypeAny:
+		return enc.AppendReflected(field.Interface)
+	}
+
+	return nil
+}
+
+//nolint:wrapcheck //encoder errors are handled by zap
+func appendNestedField(enc zapcore.ArrayEncoder, field logger.Field, redact func(string) string) error {
+	switch value := field.Interface.(type) {
+	case time.Time:
+		enc.AppendTime(value)
+	case error:
+		return enc.AppendObject(newErrorObject(value, redact))
+	case []logger.Field:
+		if field.Type == logger.FieldTypeArray {
+			return enc.AppendArray(fieldsArray{elems: value, redact: redact})
+		}
+
+		return enc.AppendObject(fieldsObject{fields: value, redact: redact})
+	default:
+		return enc.AppendReflected(value)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestGetFieldsAsZapFields(t *testing.T) {
+	now := time.Date(2024, 10, 2, 15, 4, 5, 0, time.UTC)
+
+	fields := standardlogger.GetFieldsAsZapFields([]logger.Field{
+		logger.String("s", "v"),
+		logger.Int("i", -1),
+		logger.Uint64("u", 1),
+		logger.Float64("f", 1.5),
+		logger.Bool("b", true),
+		logger.Duration("d", time.Second),
+		logger.Time("t", now),
+		logger.Err(nil),
+	})
+
+	expected := []zap.Field{
+		zap.String("s", "v"),
+		zap.Int64("i", -1),
+		zap.Uint64("u", 1),
+		zap.Float64("f", 1.5),
+		zap.Bool("b", true),
+		zap.Duration("d", time.Second),
+		zap.Time("t", now),
+	}
+
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %d.", len(expected), len(fields))
+	}
+
+	for i := range expected {
+		if !fields[i].Equals(expected[i]) {
+			t.Errorf("Wrong field %+v, want %+v.", fields[i], expected[i])
+		}
+
+		if fields[i].Type == zapcore.ReflectType {
+			t.Errorf("Field %s converted with reflection.", fields[i].Key)
+		}
+	}
+}
+
+func TestStandardLog_InfoWithKeepsOrder(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+	)
+
+	log.InfoWith("Info msg",
+		logger.String("z", "last"),
+		logger.Object("user", logger.String("name", "Ann"), logger.Int("age", 30)),
+		logger.Array("tags", logger.String("", "a"), logger.Int("", 1), logger.Object("", logger.Bool("ok", true))),
+		logger.Ints("ids", []int{1, 2}),
+		logger.Any("meta", logger.F{"k": "v"}),
+		logger.String("a", "first"),
+	)
+
+	expected := `"z":"last","user":{"name":"Ann","age":30},"tags":["a",1,{"ok":true}],"ids":[1,2],"meta":{"k":"v"},"a":"first"`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Fields out of order in %s, want %s", buf.String(), expected)
+	}
+}
+
+func TestStandardLog_ErrorWith(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil)
+
+	log.ErrorWith("Error msg", 1000, logger.Err(errors.New("failed")), logger.String("id", "1"))
+
+	entry := logs.All()[0]
+	if !standardlogger.HasField(entry, zap.Uint64("code", 1000)) || !standardlogger.HasField(entry, zap.String("id", "1")) {
+		t.Errorf("Wrong fields %v.", entry.ContextMap())
+	}
+
+	object, _ := entry.ContextMap()["error"].(map[string]interface{})
+	if object["message"] != "failed" {
+		t.Errorf("Wrong error %v.", entry.ContextMap()["error"])
+	}
+}
+
+func TestStandardLog_LevelsWith(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithLogLevel(logger.LevelTrace))
+
+	log.TraceWith("msg", logger.Int("n", 1))
+	log.DebugWith("msg", logger.Int("n", 2))
+	log.WarnWith("msg", logger.Int("n", 3))
+
+	for i, entry := range standardlogger.FindByMessage(logs, "msg") {
+		if !standardlogger.HasField(entry, zap.Int64("n", int64(i+1))) {
+			t.Errorf("Wrong fields %v.", entry.ContextMap())
+		}
+	}
+
+	if logs.Len() != 3 {
+		t.Errorf("Expected 3 entries, got %d.", logs.Len())
+	}
+}
+
+func TestStandardLog_PanicWith(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil)
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		defer log.PanicLogger()
+
+		log.PanicWith("Panic msg", 1000, logger.String("id", "1"))
+	}()
+
+	entries := standardlogger.FindByMessage(logs, "Panic msg")
+	if len(entries) != 1 || !standardlogger.HasField(entries[0], zap.String("id", "1")) {
+		t.Errorf("Wrong panic entries %v.", entries)
+	}
+}
+
+func TestStandardLog_InfoWithRedaction(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithRedaction(
+		standardlogger.RedactionRule{Keys: []string{"password"}, Strategy: standardlogger.RedactMask},
+		standardlogger.RedactionRule{Keys: []string{"pin"}, Strategy: standardlogger.RedactDrop},
+	))
+
+	log.InfoWith("Info msg",
+		logger.String("password", "hunter2"),
+		logger.Int("pin", 1234),
+		logger.Object("user", logger.String("password", "hunter2"), logger.String("name", "Ann")),
+	)
+
+	fields := logs.All()[0].ContextMap()
+	user, _ := fields["user"].(map[string]interface{})
+
+	if fields["password"] != "[REDACTED]" || user["password"] != "[REDACTED]" || user["name"] != "Ann" {
+		t.Errorf("Wrong fields %v.", fields)
+	}
+
+	if _, ok := fields["pin"]; ok {
+		t.Error("Field pin present.")
+	}
+}
@@ -0,0 +1,526 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filesink provides a log file output that rotates by size and time, keeps a limited number of gzip compressed backups and can be reopened on SIGHUP for logrotate compatibility.
+package filesink
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	fileMode         = 0o600
+	dirMode          = 0o755
+)
+
+// ErrClosed is returned when writing to a closed Sink.
+var ErrClosed = errors.New("file sink closed")
+
+// Sink is an io.Writer and zapcore.WriteSyncer that writes to a file and rotates it.
+// Rotated files are renamed to <name>-<timestamp><ext> in the same directory.
+// Files rotated within the same millisecond get a counter suffix, as in <name>-<timestamp>.1<ext>.
+type Sink struct {
+	path     string
+	settings sinkSettings
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	millCh  chan struct{}
+	signals chan os.Signal
+	wg      sync.WaitGroup
+}
+
+type Option func(*sinkSettings)
+
+type sinkSettings struct {
+	maxSize          int64
+	rotationInterval time.Duration
+	maxBackups       int
+	maxAge           time.Duration
+	compress         bool
+	reopenOnSIGHUP   bool
+	errorHandler     func(error)
+}
+
+var defaultSettings = sinkSettings{
+	errorHandler: func(err error) {
+		fmt.Fprintf(os.Stderr, "filesink: %v\n", err)
+	},
+}
+
+// WithMaxSize returns Option that rotates the file before it grows over maxSize bytes.
+func WithMaxSize(maxSize int64) Option {
+	return func(ss *sinkSettings) {
+		ss.maxSize = maxSize
+	}
+}
+
+// WithRotationInterval returns Option that rotates the file every interval, aligned to the start of the interval in UTC.
+// For example, an interval of 24 hours rotates the file at midnight UTC.
+func WithRotationInterval(interval time.Duration) Option {
+	return func(ss *sinkSettings) {
+		ss.rotationInterval = interval
+	}
+}
+
+// WithMaxBackups returns Option that keeps at most maxBackups rotated files.
+func WithMaxBackups(maxBackups int) Option {
+	return func(ss *sinkSettings) {
+		ss.maxBackups = maxBackups
+	}
+}
+
+// WithMaxAge returns Option that deletes rotated files older than maxAge.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(ss *sinkSettings) {
+		ss.maxAge = maxAge
+	}
+}
+
+// WithCompression returns Option that gzips rotated files.
+func WithCompression() Option {
+	return func(ss *sinkSettings) {
+		ss.compress = true
+	}
+}
+
+// WithReopenOnSIGHUP returns Option that reopens the file when the process receives SIGHUP,
+// which is what logrotate expects after moving the file.
+func WithReopenOnSIGHUP() Option {
+	return func(ss *sinkSettings) {
+		ss.reopenOnSIGHUP = true
+	}
+}
+
+// WithErrorHandler returns Option that sets the handler of errors that happen in the background,
+// such as failures to compress or delete rotated files. By default, they are written to stderr.
+func WithErrorHandler(handler func(error)) Option {
+	return func(ss *sinkSettings) {
+		ss.errorHandler = handler
+	}
+}
+
+// New opens or creates the file at path, creating its directory if needed.
+func New(path string, opts ...Option) (*Sink, error) {
+	settings := defaultSettings
+
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	sink := &Sink{
+		path:     path,
+		settings: settings,
+		millCh:   make(chan struct{}, 1),
+	}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	sink.wg.Add(1)
+
+	go sink.mill()
+
+	if settings.reopenOnSIGHUP {
+		sink.signals = make(chan os.Signal, 1)
+		signal.Notify(sink.signals, syscall.SIGHUP)
+
+		sink.wg.Add(1)
+
+		go sink.handleSignals()
+	}
+
+	// clean up backups left over from previous runs.
+	sink.triggerMill()
+
+	return sink, nil
+}
+
+// Write writes p to the file, rotating it first if needed.
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrClosed
+	}
+
+	if s.shouldRotate(int64(len(p))) {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+
+	return n, err //nolint:wrapcheck //returned as is to satisfy io.Writer
+}
+
+// Sync commits the file contents to stable storage.
+func (s *Sink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	return s.file.Sync() //nolint:wrapcheck //returned as is to satisfy zapcore.WriteSyncer
+}
+
+// Rotate renames the current file to a backup and opens a new one.
+func (s *Sink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	return s.rotate()
+}
+
+// Reopen closes and reopens the file at the configured path, for example after logrotate moved it.
+func (s *Sink) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", s.path, err)
+	}
+
+	return s.open()
+}
+
+// Close closes the file and waits for compression and cleanup of rotated files to finish.
+// Closing an already closed Sink does nothing.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	s.closed = true
+	err := s.file.Close()
+
+	if s.signals != nil {
+		signal.Stop(s.signals)
+		close(s.signals)
+	}
+
+	close(s.millCh)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	if err != nil {
+		return fmt.Errorf("closing %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *Sink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), dirMode); err != nil {
+		return fmt.Errorf("creating directory of %s: %w", s.path, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileMode)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", s.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("reading size of %s: %w", s.path, err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	s.openedAt = time.Now()
+
+	return nil
+}
+
+func (s *Sink) shouldRotate(writeSize int64) bool {
+	if s.settings.maxSize > 0 && s.size > 0 && s.size+writeSize > s.settings.maxSize {
+		return true
+	}
+
+	if s.settings.rotationInterval > 0 {
+		interval := s.settings.rotationInterval
+
+		return !time.Now().UTC().Truncate(interval).Equal(s.openedAt.UTC().Truncate(interval))
+	}
+
+	return false
+}
+
+func (s *Sink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", s.path, err)
+	}
+
+	if err := os.Rename(s.path, s.backupName(time.Now())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("renaming %s: %w", s.path, err)
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	s.triggerMill()
+
+	return nil
+}
+
+// backupName returns the first name for a file rotated at t that isn't taken by an earlier backup, compressed or not.
+func (s *Sink) backupName(t time.Time) string {
+	dir, prefix, ext := s.nameParts()
+	stamp := t.UTC().Format(backupTimeFormat)
+
+	name := filepath.Join(dir, prefix+stamp+ext)
+	for counter := 1; exists(name) || exists(name+compressSuffix); counter++ {
+		name = filepath.Join(dir, prefix+stamp+"."+strconv.Itoa(counter)+ext)
+	}
+
+	return name
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func (s *Sink) nameParts() (string, string, string) {
+	dir := filepath.Dir(s.path)
+	name := filepath.Base(s.path)
+	ext := filepath.Ext(name)
+
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+func (s *Sink) triggerMill() {
+	select {
+	case s.millCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Sink) mill() {
+	defer s.wg.Done()
+
+	for range s.millCh {
+		if err := s.compressAndPrune(); err != nil {
+			s.settings.errorHandler(err)
+		}
+	}
+}
+
+func (s *Sink) handleSignals() {
+	defer s.wg.Done()
+
+	for range s.signals {
+		if err := s.Reopen(); err != nil && !errors.Is(err, ErrClosed) {
+			s.settings.errorHandler(err)
+		}
+	}
+}
+
+type backup struct {
+	path      string
+	timestamp time.Time
+	counter   int
+}
+
+func (s *Sink) compressAndPrune() error {
+	backups, err := s.backups()
+	if err != nil {
+		return err
+	}
+
+	// newest first.
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].timestamp.Equal(backups[j].timestamp) {
+			return backups[i].counter > backups[j].counter
+		}
+
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+
+	var errs []string
+
+	for i, b := range backups {
+		if s.isExpired(i, b) {
+			if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err.Error())
+			}
+
+			continue
+		}
+
+		if s.settings.compress && !strings.HasSuffix(b.path, compressSuffix) {
+			if err := compress(b.path); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("cleaning up rotated files of %s: %s", s.path, strings.Join(errs, "; ")) //nolint:goerr113 //aggregated errors
+	}
+
+	return nil
+}
+
+func (s *Sink) isExpired(index int, b backup) bool {
+	if s.settings.maxBackups > 0 && index >= s.settings.maxBackups {
+		return true
+	}
+
+	return s.settings.maxAge > 0 && time.Since(b.timestamp) > s.settings.maxAge
+}
+
+func (s *Sink) backups() ([]backup, error) {
+	dir, prefix, ext := s.nameParts()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing rotated files of %s: %w", s.path, err)
+	}
+
+	var backups []backup
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, compressSuffix)
+
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+
+		timestamp, counter, ok := parseStamp(strings.TrimSuffix(stamp, ext))
+		if !ok {
+			continue
+		}
+
+		backups = append(backups, backup{path: filepath.Join(dir, name), timestamp: timestamp, counter: counter})
+	}
+
+	return backups, nil
+}
+
+// parseStamp parses the timestamp and the optional counter suffix of a backup name.
+func parseStamp(stamp string) (time.Time, int, bool) {
+	counter := 0
+
+	if len(stamp) > len(backupTimeFormat) {
+		suffix := stamp[len(backupTimeFormat):]
+		if !strings.HasPrefix(suffix, ".") {
+			return time.Time{}, 0, false
+		}
+
+		n, err := strconv.Atoi(suffix[1:])
+		if err != nil || n < 1 {
+			return time.Time{}, 0, false
+		}
+
+		stamp, counter = stamp[:len(backupTimeFormat)], n
+	}
+
+	timestamp, err := time.Parse(backupTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	return timestamp, counter, true
+}
+
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer src.Close()
+
+	tmpPath := path + compressSuffix + ".tmp"
+
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fileMode)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", tmpPath, err)
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmpPath)
+
+		return fmt.Errorf("compressing %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmpPath, path+compressSuffix); err != nil {
+		return fmt.Errorf("renaming %s: %w", tmpPath, err)
+	}
+
+	src.Close()
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing %s: %w", path, err)
+	}
+
+	return nil
+}
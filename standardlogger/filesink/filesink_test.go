@@ -0,0 +1,358 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesink_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/standardlogger/filesink"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func TestNew_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	sink, err := filesink.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = sink.Write([]byte("line\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if content := readFile(t, path); content != "line\n" {
+		t.Errorf("Wrong content %q, want %q.", content, "line\n")
+	}
+}
+
+func TestSink_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := filesink.New(path, filesink.WithMaxSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err = sink.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+
+		// backups are named by millisecond timestamps.
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := listDir(t, dir)
+
+	expectedNumberOfFiles := 3
+	if len(names) != expectedNumberOfFiles {
+		t.Fatalf("Wrong files %v, want %d.", names, expectedNumberOfFiles)
+	}
+
+	if content := readFile(t, path); content != "third\n" {
+		t.Errorf("Wrong content %q, want %q.", content, "third\n")
+	}
+
+	if content := readFile(t, filepath.Join(dir, names[0])); content != "first\n" {
+		t.Errorf("Wrong oldest backup content %q, want %q.", content, "first\n")
+	}
+}
+
+func TestSink_MaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := filesink.New(path, filesink.WithMaxBackups(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err = sink.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = sink.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the current file and two backups.
+	expectedNumberOfFiles := 3
+	if names := listDir(t, dir); len(names) != expectedNumberOfFiles {
+		t.Errorf("Wrong files %v, want %d.", names, expectedNumberOfFiles)
+	}
+}
+
+func TestSink_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	old := filepath.Join(dir, "app-"+time.Now().Add(-48*time.Hour).UTC().Format("2006-01-02T15-04-05.000")+".log")
+	if err := os.WriteFile(old, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := filesink.New(path, filesink.WithMaxAge(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expired backup %s not deleted.", old)
+	}
+}
+
+func TestSink_Compression(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := filesink.New(path, filesink.WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = sink.Write([]byte("compressed\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed string
+
+	for _, name := range listDir(t, dir) {
+		if strings.HasSuffix(name, ".log.gz") {
+			compressed = filepath.Join(dir, name)
+		} else if name != "app.log" {
+			t.Errorf("Unexpected file %s.", name)
+		}
+	}
+
+	if compressed == "" {
+		t.Fatal("Compressed backup missing.")
+	}
+
+	file, err := os.Open(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "compressed\n" {
+		t.Errorf("Wrong content %q, want %q.", content, "compressed\n")
+	}
+}
+
+func TestSink_RotatesByInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := filesink.New(path, filesink.WithRotationInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = sink.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, err = sink.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := listDir(t, dir); len(names) < 2 {
+		t.Errorf("Wrong files %v, want a backup.", names)
+	}
+
+	if content := readFile(t, path); content != "second\n" {
+		t.Errorf("Wrong content %q, want %q.", content, "second\n")
+	}
+}
+
+func TestSink_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	moved := filepath.Join(dir, "app.log.1")
+
+	sink, err := filesink.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = sink.Write([]byte("before\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// what logrotate does before sending SIGHUP.
+	if err = os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = sink.Write([]byte("after\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if content := readFile(t, moved); content != "before\n" {
+		t.Errorf("Wrong moved content %q, want %q.", content, "before\n")
+	}
+
+	if content := readFile(t, path); content != "after\n" {
+		t.Errorf("Wrong content %q, want %q.", content, "after\n")
+	}
+}
+
+func TestSink_Close(t *testing.T) {
+	sink, err := filesink.New(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Errorf("Second Close returned %v.", err)
+	}
+
+	if _, err = sink.Write([]byte("line\n")); !errors.Is(err, filesink.ErrClosed) {
+		t.Errorf("Write after Close returned %v, want %v.", err, filesink.ErrClosed)
+	}
+}
+
+func TestSink_RotatesWithinMillisecond(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := filesink.New(path, filesink.WithMaxBackups(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rotations without sleeping in between fall into the same millisecond.
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n", "fifth\n"} {
+		if _, err = sink.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = sink.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var contents []string
+
+	for _, name := range listDir(t, dir) {
+		if name != "app.log" {
+			contents = append(contents, readFile(t, filepath.Join(dir, name)))
+		}
+	}
+
+	sort.Strings(contents)
+
+	if expected := []string{"fifth\n", "fourth\n", "third\n"}; strings.Join(contents, "") != strings.Join(expected, "") {
+		t.Errorf("Wrong backups %q, want %q.", contents, expected)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Format selects how entries are encoded.
+type Format int
+
+const (
+	// FormatAuto selects FormatConsole when the output is a terminal and FormatJSON otherwise,
+	// or FormatJSON if a profile other than ProfileDefault is set.
+	FormatAuto Format = iota + 1
+	// FormatJSON encodes every entry as a single line JSON object.
+	FormatJSON
+	// FormatConsole encodes entries in a colorized, human-readable format for local development.
+	// Colors are disabled if the NO_COLOR environment variable is set.
+	FormatConsole
+	// FormatLogfmt encodes every entry as a single line of logfmt key=value pairs.
+	FormatLogfmt
+)
+
+// WithFormat returns Option that sets the format of the entries.
+func WithFormat(format Format) Option {
+	return func(ls *loggerSettings) {
+		ls.format = format
+	}
+}
+
+func newEncoder(settings loggerSettings) zapcore.Encoder {
+	switch resolveFormat(settings) {
+	case FormatConsole:
+		_, noColor := os.LookupEnv("NO_COLOR")
+
+		return newConsoleEncoder(!noColor)
+	case FormatLogfmt:
+		return newLogfmtEncoder()
+	default:
+		return newJSONEncoder(getSchema(settings))
+	}
+}
+
+// resolveFormat returns the format to use, replacing FormatAuto with the format it selects.
+// Profiles other than ProfileDefault target a log backend, so they select FormatJSON even on terminals.
+func resolveFormat(settings loggerSettings) Format {
+	if settings.format != FormatAuto {
+		return settings.format
+	}
+
+	if settings.profile != ProfileDefault {
+		return FormatJSON
+	}
+
+	if isTerminal(settings.output) {
+		return FormatConsole
+	}
+
+	return FormatJSON
+}
+
+func newJSONEncoder(schema *schema) zapcore.Encoder {
+	// Set timestamp to be in RFC3339Nano format.
+	// This format is easily human-readable, unlike unix timestamp.
+	// Fluent Bit can parse this format without custom scripting.
+	conf := zap.NewProductionEncoderConfig()
+	conf.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	conf.EncodeLevel = levelEncoder
+	schema.encoderConfig(&conf)
+
+	encoder := zapcore.NewJSONEncoder(conf)
+	if schema.encodeEntry == nil {
+		return encoder
+	}
+
+	return &schemaEncoder{Encoder: encoder, schema: schema}
+}
+
+// isTerminal reports whether the output, stdout if nil, is a character device such as a terminal.
+func isTerminal(output io.Writer) bool {
+	if output == nil {
+		output = os.Stdout
+	}
+
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestWithFormat_Console(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{"product": "Persistor", "license": "enterprise"},
+		standardlogger.WithFormat(standardlogger.FormatConsole),
+		standardlogger.WithSingleOutput(&output),
+	)
+
+	log.Infow("Info msg", logger.F{"reqId": 22, "user": "John Doe"})
+
+	line := output.String()
+
+	for _, wantSubstring := range []string{
+		"INFO  ",
+		"standardlogger/format_test.go:",
+		"Info msg [license=enterprise product=Persistor] reqId=22 user=\"John Doe\"\n",
+	} {
+		if !strings.Contains(line, wantSubstring) {
+			t.Errorf("Output %q missing, want substring '%s'.", line, wantSubstring)
+		}
+	}
+
+	if strings.Contains(line, "tags") {
+		t.Errorf("Output %q contains tags.", line)
+	}
+
+	if strings.Contains(line, "\x1b[") {
+		t.Errorf("Output %q colored although NO_COLOR is set.", line)
+	}
+}
+
+func TestWithFormat_ConsoleStacktrace(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{},
+		standardlogger.WithFormat(standardlogger.FormatConsole),
+		standardlogger.WithSingleOutput(&output),
+	)
+
+	log.Error("Error msg", 1000)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Stack trace missing in %q.", output.String())
+	}
+
+	if !strings.Contains(lines[0], "ERROR") || !strings.Contains(lines[0], "code=1000") {
+		t.Errorf("Wrong first line %q.", lines[0])
+	}
+
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "    ") {
+			t.Errorf("Stack trace line %q not indented.", line)
+		}
+	}
+}
+
+func TestWithFormat_ConsoleColors(t *testing.T) {
+	if noColor, ok := os.LookupEnv("NO_COLOR"); ok {
+		os.Unsetenv("NO_COLOR")
+		defer os.Setenv("NO_COLOR", noColor)
+	}
+
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{},
+		standardlogger.WithFormat(standardlogger.FormatConsole),
+		standardlogger.WithSingleOutput(&output),
+	)
+
+	log.Warn("Warn msg")
+
+	if !strings.Contains(output.String(), "\x1b[33mWARN") {
+		t.Errorf("Output %q missing colored level.", output.String())
+	}
+}
+
+func TestWithFormat_AutoSelectsJSONWhenNotTerminal(t *testing.T) {
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{"key0": "val0"}, standardlogger.WithSingleOutput(&output))
+	log.Info("Info msg")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Errorf("Output %q is not JSON: %v.", output.String(), err)
+	}
+}
+
+func TestWithFormat_AutoKeepsProfileOnTerminal(t *testing.T) {
+	// /dev/null is a character device, so it is treated as a terminal.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("Opening %s failed: %v.", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	log, logs := standardlogger.NewForTesting(nil, standardlogger.WithSingleOutput(devNull))
+	log.Error("Error msg", 1000)
+
+	if !standardlogger.HasField(logs.All()[0], zap.Uint64("code", 1000)) {
+		t.Fatalf("Expected the default schema on a terminal, got %v.", logs.All()[0].ContextMap())
+	}
+
+	log, logs = standardlogger.NewForTesting(nil,
+		standardlogger.WithSingleOutput(devNull),
+		standardlogger.WithProfile(standardlogger.ProfileECS),
+	)
+	log.Error("Error msg", 1000)
+
+	if !standardlogger.HasField(logs.All()[0], zap.String("error.code", "1000")) {
+		t.Errorf("Profile dropped on a terminal, got %v.", logs.All()[0].ContextMap())
+	}
+}
+
+func TestWithFormat_JSON(t *testing.T) {
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{},
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithSingleOutput(&output),
+	)
+	log.Info("Info msg")
+
+	if !strings.Contains(output.String(), "\"msg\":\"Info msg\"") {
+		t.Errorf("Output %q is not JSON.", output.String())
+	}
+}
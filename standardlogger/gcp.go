@@ -0,0 +1,166 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const (
+	gcpStackTraceKey = "stack_trace"
+	// gcpGoroutineHeader starts stack traces in the format of runtime.Stack, which Error Reporting parses for Go.
+	gcpGoroutineHeader = "goroutine 1 [running]:"
+)
+
+// gcpErrorEventType marks entries that Error Reporting should pick up.
+const gcpErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// gcpSchema follows the Cloud Logging structured logging format, see https://cloud.google.com/logging/docs/structured-logging.
+var gcpSchema = &schema{
+	codeField: defaultSchema.codeField,
+	labelFields: func(labels logger.Labels) []zap.Field {
+		return []zap.Field{
+			zap.Object("logging.googleapis.com/labels", labelsObject(labels)),
+			zap.Strings(tagsKey, GetLabelsKeys(labels)),
+		}
+	},
+	encoderConfig: func(conf *zapcore.EncoderConfig) {
+		conf.TimeKey = "time"
+		conf.LevelKey = "severity"
+		conf.MessageKey = "message"
+		conf.StacktraceKey = gcpStackTraceKey
+		conf.EncodeLevel = gcpSeverityEncoder
+		// the caller is emitted as logging.googleapis.com/sourceLocation by encodeEntry.
+		conf.CallerKey = zapcore.OmitKey
+	},
+	// Error Reporting groups error entries by their stack trace and report location,
+	// see https://cloud.google.com/error-reporting/docs/formatting-error-messages.
+	encodeEntry: func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+		var entryFields []zap.Field
+
+		if ent.Caller.Defined {
+			entryFields = append(entryFields, zap.Object("logging.googleapis.com/sourceLocation", gcpSourceLocation(ent.Caller)))
+		}
+
+		if ent.Level >= zapcore.ErrorLevel {
+			entryFields = append(entryFields, zap.String("@type", gcpErrorEventType))
+
+			if ent.Caller.Defined {
+				entryFields = append(entryFields, zap.Object("context", gcpErrorContext(ent.Caller)))
+			}
+		}
+
+		// the error's own stack trace replaces the one of the entry, it is written at the top level in both cases.
+		stack := ent.Stack
+		ent.Stack = ""
+
+		shaped := make([]zapcore.Field, 0, len(fields))
+
+		for _, field := range fields {
+			if obj, ok := field.Interface.(errorObject); ok && field.Key == errorKey && obj.stack != "" {
+				stack = obj.stack
+				field = zap.Object(errorKey, obj.withoutStack())
+			}
+
+			shaped = append(shaped, field)
+		}
+
+		if stack != "" {
+			entryFields = append(entryFields, zap.String(gcpStackTraceKey, gcpGoStack(ent.Message, stack)))
+		}
+
+		return ent, prependFields(entryFields, shaped)
+	},
+	reservedKeys: []string{
+		"time", "severity", "message", gcpStackTraceKey, "logging.googleapis.com/labels",
+		"logging.googleapis.com/sourceLocation", "@type", "context", "code", tagsKey,
+	},
+}
+
+// gcpSeverityEncoder encodes levels as Cloud Logging LogSeverity names.
+func gcpSeverityEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	var severity string
+
+	switch {
+	case lvl <= zapcore.DebugLevel:
+		severity = "DEBUG"
+	case lvl == zapcore.InfoLevel:
+		severity = "INFO"
+	case lvl == zapcore.WarnLevel:
+		severity = "WARNING"
+	case lvl == zapcore.ErrorLevel:
+		severity = "ERROR"
+	case lvl == zapcore.DPanicLevel:
+		severity = "CRITICAL"
+	case lvl == zapcore.PanicLevel:
+		severity = "ALERT"
+	default:
+		severity = "EMERGENCY"
+	}
+
+	enc.AppendString(severity)
+}
+
+// gcpErrorContext marshals the context of a ReportedErrorEvent with the location of the call site.
+func gcpErrorContext(caller zapcore.EntryCaller) zapcore.ObjectMarshalerFunc {
+	return func(enc zapcore.ObjectEncoder) error {
+		return enc.AddObject("reportLocation", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("filePath", callerFile(caller))
+			enc.AddInt("lineNumber", caller.Line)
+			enc.AddString("functionName", caller.Function)
+
+			return nil
+		}))
+	}
+}
+
+// gcpGoStack rewrites a stack trace of zap or github.com/pkg/errors, i.e. function names each followed by
+// a tab-indented file:line, into the format of runtime.Stack, preceded by the message.
+func gcpGoStack(message, stack string) string {
+	var b strings.Builder
+
+	b.WriteString(message)
+	b.WriteString("\n\n")
+	b.WriteString(gcpGoroutineHeader)
+
+	for _, line := range strings.Split(strings.TrimSpace(stack), "\n") {
+		b.WriteString("\n")
+		b.WriteString(line)
+
+		// runtime.Stack writes the arguments of a function after its name, (...) if they are elided.
+		if line != "" && !strings.HasPrefix(line, "\t") && !strings.HasSuffix(line, ")") {
+			b.WriteString("(...)")
+		}
+	}
+
+	return b.String()
+}
+
+func gcpSourceLocation(caller zapcore.EntryCaller) zapcore.ObjectMarshalerFunc {
+	return func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("file", callerFile(caller))
+		// line is an int64, which the LogEntry JSON representation encodes as a string.
+		enc.AddString("line", strconv.Itoa(caller.Line))
+		enc.AddString("function", caller.Function)
+
+		return nil
+	}
+}
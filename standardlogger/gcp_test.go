@@ -0,0 +1,121 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestWithProfile_GCPSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(logger.Log)
+		expected string
+	}{
+		{"info", func(log logger.Log) { log.Info("msg") }, "INFO"},
+		{"warn", func(log logger.Log) { log.Warn("msg") }, "WARNING"},
+		{"error", func(log logger.Log) { log.Error("msg", 0) }, "ERROR"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			entry := logProfileEntry(t, standardlogger.ProfileGCP, test.log)
+
+			if entry["severity"] != test.expected {
+				t.Errorf("Wrong severity %v, want %s.", entry["severity"], test.expected)
+			}
+		})
+	}
+}
+
+func TestWithProfile_GCP(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileGCP, func(log logger.Log) {
+		log.Info("Info msg")
+	})
+
+	if entry["message"] != "Info msg" {
+		t.Errorf("Wrong message %v.", entry["message"])
+	}
+
+	for _, key := range []string{"time", "logging.googleapis.com/sourceLocation"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("Key %s missing.", key)
+		}
+	}
+
+	for _, key := range []string{"ts", "level", "msg", "caller", "product", "@type"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Key %s present.", key)
+		}
+	}
+
+	labels, ok := entry["logging.googleapis.com/labels"].(map[string]interface{})
+	if !ok || labels["product"] != "Persistor" {
+		t.Errorf("Wrong labels %v.", entry["logging.googleapis.com/labels"])
+	}
+
+	location, ok := entry["logging.googleapis.com/sourceLocation"].(map[string]interface{})
+	if !ok || location["file"] != "standardlogger/gcp_test.go" || location["line"] == "" || location["function"] == "" {
+		t.Errorf("Wrong source location %v.", entry["logging.googleapis.com/sourceLocation"])
+	}
+}
+
+func TestWithProfile_GCPErrorReporting(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileGCP, func(log logger.Log) {
+		log.Error("Error msg", 1000)
+	})
+
+	expectedType := "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+	if entry["@type"] != expectedType {
+		t.Errorf("Wrong @type %v, want %s.", entry["@type"], expectedType)
+	}
+
+	if entry["code"] != float64(1000) {
+		t.Errorf("Wrong code %v, want 1000.", entry["code"])
+	}
+
+	stack, _ := entry["stack_trace"].(string)
+	if !strings.HasPrefix(stack, "Error msg\n\ngoroutine 1 [running]:\n") ||
+		!strings.Contains(stack, ".TestWithProfile_GCPErrorReporting.func1(...)\n\t") ||
+		!strings.Contains(stack, "gcp_test.go:") {
+		t.Errorf("Stack trace not in the format of runtime.Stack:\n%s", stack)
+	}
+
+	context, _ := entry["context"].(map[string]interface{})
+	location, _ := context["reportLocation"].(map[string]interface{})
+
+	function, _ := location["functionName"].(string)
+	if location["filePath"] != "standardlogger/gcp_test.go" || location["lineNumber"] == float64(0) ||
+		!strings.HasSuffix(function, "TestWithProfile_GCPErrorReporting.func1") {
+		t.Errorf("Wrong report location %v.", entry["context"])
+	}
+}
+
+func TestWithProfile_GCPInfoHasNoErrorContext(t *testing.T) {
+	entry := logProfileEntry(t, standardlogger.ProfileGCP, func(log logger.Log) {
+		log.Warn("Warn msg")
+	})
+
+	for _, key := range []string{"@type", "context", "stack_trace"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("Key %s present.", key)
+		}
+	}
+}
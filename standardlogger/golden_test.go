@@ -0,0 +1,130 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var (
+	timestampPattern  = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z`)
+	stackTracePattern = regexp.MustCompile(`"[^"]*\\n\\t[^"]*"`)
+	callerLinePattern = regexp.MustCompile(`(golden_test\.go:|"line":"|"lineNumber":|"log\.origin\.file\.line":)\d+`)
+)
+
+// normalizeGolden replaces the parts of entries that change between runs and machines,
+// as well as the caller line numbers, so that editing this file does not break the golden files.
+func normalizeGolden(out []byte) []byte {
+	out = timestampPattern.ReplaceAll(out, []byte("2024-01-01T00:00:00Z"))
+	out = callerLinePattern.ReplaceAll(out, []byte("${1}0"))
+
+	return stackTracePattern.ReplaceAll(out, []byte(`"<stack>"`))
+}
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile standardlogger.Profile
+	}{
+		{"default", standardlogger.ProfileDefault},
+		{"ecs", standardlogger.ProfileECS},
+		{"gcp", standardlogger.ProfileGCP},
+		{"azure", standardlogger.ProfileAzure},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			log := standardlogger.New(logger.L{"product": "Persistor", "component": "reader", "env": "test"},
+				standardlogger.WithSingleOutput(&buf),
+				standardlogger.WithFormat(standardlogger.FormatJSON),
+				standardlogger.WithProfile(test.profile),
+				standardlogger.WithLogLevel(logger.LevelTrace),
+			)
+
+			child := log.WithLabels(logger.L{"app": "ingest", "batch": "7"}).With(logger.F{"zone": "eu", "attempt": 2})
+
+			child.Infow("Batch read", logger.F{"topic": "orders", "count": 10, "meta": logger.F{"size": 512, "codec": "gzip"}})
+			child.Debug("Batch parsed")
+			child.Warnw("Batch slow", logger.F{"took": "2s", "limit": "1s"})
+			log.InfoWith("Typed", logger.String("z", "last"), logger.Int("a", 1))
+			log.Errorw("Batch failed", 1000, logger.F{"topic": "orders", "offset": 42})
+			log.ErrorE("Commit failed", 1001, errors.New("broker unreachable"), nil)
+
+			assertGolden(t, filepath.Join("testdata", "golden", test.name+".golden"), normalizeGolden(buf.Bytes()))
+		})
+	}
+}
+
+func TestGolden_Repeatable(t *testing.T) {
+	var first []byte
+
+	for i := 0; i < 20; i++ {
+		var buf bytes.Buffer
+
+		log := standardlogger.New(logger.L{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5"},
+			standardlogger.WithSingleOutput(&buf),
+			standardlogger.WithFormat(standardlogger.FormatJSON),
+		)
+
+		log.Infow("msg", logger.F{"f": 1, "g": 2, "h": 3, "i": 4, "j": 5})
+
+		out := normalizeGolden(buf.Bytes())
+		if first == nil {
+			first = out
+		}
+
+		if !bytes.Equal(first, out) {
+			t.Fatalf("Output changed between runs:\n%s\n%s", first, out)
+		}
+	}
+}
+
+func assertGolden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading golden file failed, run go test with -update to create it: %v", err)
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("Output differs from %s, run go test with -update if the change is intended.\ngot:\n%s\nwant:\n%s", path, actual, expected)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+// WithLabelPolicy returns Option that validates labels passed to New and WithLabels with policy
+// and renames field keys that collide with the policy's reserved keys, adding the policy's prefix as for labels.
+// Besides the policy's reserved keys, the keys written by the profile, such as message in ProfileECS,
+// and the error, code_name and category keys are reserved for labels and fields added with With.
+// The keys written only in some entries, i.e. the error code, code_name, category and error keys,
+// are reserved for fields of a single entry only if the entry has them.
+// In logger.LabelModeStrict, labels that break the policy are dropped and reported with a warning entry,
+// use Labels.Validate before creating the logger to get the errors instead.
+func WithLabelPolicy(policy logger.LabelPolicy) Option {
+	return func(ls *loggerSettings) {
+		ls.labelPolicy = &policy
+	}
+}
+
+// newLabelPolicy returns policy with the keys written by schema and the logger added to its reserved keys.
+func newLabelPolicy(policy *logger.LabelPolicy, schema *schema) *logger.LabelPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	reserved := policy.ReservedKeys
+	if reserved == nil {
+		reserved = logger.ReservedKeys
+	}
+
+	merged := *policy
+	merged.ReservedKeys = make([]string, 0, len(reserved)+len(schema.reservedKeys)+3)
+	merged.ReservedKeys = append(merged.ReservedKeys, reserved...)
+	merged.ReservedKeys = append(merged.ReservedKeys, schema.reservedKeys...)
+	merged.ReservedKeys = append(merged.ReservedKeys, errorKey, codeNameKey, categoryKey)
+
+	return &merged
+}
+
+// entryKeys returns the reserved keys written only in some entries: the error code, its name and category and the error.
+func (l *StandardLog) entryKeys() []string {
+	return []string{l.getSchema().codeField(0).Key, codeNameKey, categoryKey, errorKey}
+}
+
+// isReservedField reports whether a field key collides with a key of the entry,
+// where entryKeys are the keys written only in some entries that the entry has.
+func (l *StandardLog) isReservedField(key string, entryKeys []string) bool {
+	if !l.labelPolicy.IsReserved(key) {
+		return false
+	}
+
+	return !containsKey(l.entryKeys(), key) || containsKey(entryKeys, key)
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldKeys returns the keys of fields.
+func fieldKeys(fields []zap.Field) []string {
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = field.Key
+	}
+
+	return keys
+}
+
+// validLabels returns labels that satisfy the label policy, if one is set.
+func (l *StandardLog) validLabels(labels logger.Labels) logger.Labels {
+	if l.labelPolicy == nil {
+		return labels
+	}
+
+	valid, err := labels.Validate(*l.labelPolicy)
+	if err != nil {
+		l.ZapLogger.Warn("Invalid labels dropped.", zap.Error(err))
+	}
+
+	return valid
+}
+
+// policyFields renames fields whose keys are reserved by the label policy and collide with a key of the entry.
+// A renamed field is dropped if the new key is already used by another field.
+func (l *StandardLog) policyFields(fields logger.Fields, entryKeys []string) logger.Fields {
+	if l.labelPolicy == nil || len(fields) == 0 {
+		return fields
+	}
+
+	renamed := make(logger.Fields, len(fields))
+
+	var reserved []string
+
+	for key, val := range fields {
+		if l.isReservedField(key, entryKeys) {
+			reserved = append(reserved, key)
+
+			continue
+		}
+
+		renamed[key] = val
+	}
+
+	for _, key := range reserved {
+		if _, ok := renamed[l.labelPolicy.FieldKey(key)]; !ok {
+			renamed[l.labelPolicy.FieldKey(key)] = fields[key]
+		}
+	}
+
+	return renamed
+}
+
+// policyTypedFields renames typed fields whose keys are reserved by the label policy and collide with a key of the entry.
+// Errors added with logger.Err keep the error key, which they are meant to use, so other fields with the key are renamed.
+func (l *StandardLog) policyTypedFields(fields []logger.Field, entryKeys []string) []logger.Field {
+	if l.labelPolicy == nil || len(fields) == 0 {
+		return fields
+	}
+
+	for _, field := range fields {
+		if isErrorField(field) {
+			entryKeys = append(entryKeys[:len(entryKeys):len(entryKeys)], errorKey)
+
+			break
+		}
+	}
+
+	renamed := make([]logger.Field, len(fields))
+	for i, field := range fields {
+		if !isErrorField(field) && l.isReservedField(field.Key, entryKeys) {
+			field.Key = l.labelPolicy.FieldKey(field.Key)
+		}
+
+		renamed[i] = field
+	}
+
+	return renamed
+}
+
+func isErrorField(field logger.Field) bool {
+	return field.Type == logger.FieldTypeError && field.Key == errorKey
+}
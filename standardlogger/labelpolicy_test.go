@@ -0,0 +1,249 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestWithLabelPolicy_Lenient(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{"product": "Persistor", "msg": "label"},
+		standardlogger.WithSingleOutput(&buf),
+		standardlogger.WithFormat(standardlogger.FormatJSON),
+		standardlogger.WithLabelPolicy(logger.DefaultLabelPolicy),
+	)
+
+	log.WithLabels(logger.L{"level": "high"}).Errorw("Error msg", 1000, logger.F{"code": 5, "ts": "now"})
+	log.InfoWith("Info msg", logger.String("caller", "me"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 entries, got %s", buf.String())
+	}
+
+	for _, expected := range []string{`"label_level":"high"`, `"label_msg":"label"`, `"code":1000`, `"label_code":5`, `"label_ts":"now"`} {
+		if !strings.Contains(lines[0], expected) {
+			t.Errorf("Missing %s in %s", expected, lines[0])
+		}
+	}
+
+	if strings.Count(lines[0], `"msg":`) != 1 || strings.Count(lines[0], `"code":`) != 1 {
+		t.Errorf("Duplicate keys in %s", lines[0])
+	}
+
+	if !strings.Contains(lines[1], `"label_caller":"me"`) {
+		t.Errorf("Field not renamed in %s", lines[1])
+	}
+}
+
+func TestWithLabelPolicy_Strict(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"product": "Persistor", "tags": "x"},
+		standardlogger.WithLabelPolicy(logger.LabelPolicy{Mode: logger.LabelModeStrict}),
+	)
+
+	if len(standardlogger.FindByMessage(logs, "Invalid labels dropped.")) != 1 {
+		t.Error("Invalid labels not reported.")
+	}
+
+	log.WithLabels(logger.L{"bad key": "x", "component": "reader"}).Info("Info msg")
+
+	if len(standardlogger.FindByMessage(logs, "Invalid labels dropped.")) != 2 {
+		t.Error("Invalid child labels not reported.")
+	}
+
+	entry := standardlogger.FindByMessage(logs, "Info msg")[0]
+	if !standardlogger.HasField(entry, zap.String("component", "reader")) ||
+		!standardlogger.HasField(entry, zap.String("product", "Persistor")) {
+		t.Errorf("Valid labels missing in %v.", entry.ContextMap())
+	}
+
+	if _, ok := entry.ContextMap()["bad key"]; ok {
+		t.Error("Invalid label kept.")
+	}
+
+	tags, _ := entry.ContextMap()["tags"].([]interface{})
+	if len(tags) != 2 {
+		t.Errorf("Wrong tags %v.", tags)
+	}
+}
+
+func TestWithoutLabelPolicy(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil)
+
+	log.Infow("Info msg", logger.F{"ts": "now"})
+
+	if !standardlogger.HasField(logs.All()[0], zap.String("ts", "now")) {
+		t.Error("Field renamed without a policy.")
+	}
+}
+
+// duplicateKeys returns the top-level keys that appear more than once in a JSON entry.
+func duplicateKeys(t *testing.T, line string) []string {
+	t.Helper()
+
+	dec := json.NewDecoder(strings.NewReader(line))
+	if _, err := dec.Token(); err != nil {
+		t.Fatalf("Decoding %s failed: %v", line, err)
+	}
+
+	seen := map[string]bool{}
+
+	var duplicates []string
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			t.Fatalf("Decoding %s failed: %v", line, err)
+		}
+
+		key, _ := token.(string)
+		if seen[key] {
+			duplicates = append(duplicates, key)
+		}
+
+		seen[key] = true
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			t.Fatalf("Decoding %s failed: %v", line, err)
+		}
+	}
+
+	return duplicates
+}
+
+func TestWithLabelPolicy_ProfileKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile standardlogger.Profile
+		keys    []string
+	}{
+		{"default", standardlogger.ProfileDefault, []string{"ts", "level", "msg", "caller", "stacktrace", "code", "tags"}},
+		{"ecs", standardlogger.ProfileECS, []string{"message", "@timestamp", "log.level", "error.code", "error.stack_trace", "labels", "ecs.version", "log.origin.file.name"}},
+		{"gcp", standardlogger.ProfileGCP, []string{"severity", "message", "time", "@type", "stack_trace", "logging.googleapis.com/labels", "logging.googleapis.com/sourceLocation"}},
+		{"azure", standardlogger.ProfileAzure, []string{"severityLevel", "message", "timestamp", "customDimensions", "exceptions", "caller"}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			log := standardlogger.New(logger.Labels{"product": "Persistor"},
+				standardlogger.WithSingleOutput(&buf),
+				standardlogger.WithFormat(standardlogger.FormatJSON),
+				standardlogger.WithProfile(test.profile),
+				standardlogger.WithLabelPolicy(logger.DefaultLabelPolicy),
+				standardlogger.WithErrorCodeRegistry(registryWith(1000)),
+			)
+
+			fields := logger.F{"error": "dup", "code_name": "dup", "category": "dup"}
+			typed := []logger.Field{logger.String("error", "dup")}
+
+			for _, key := range test.keys {
+				fields[key] = "dup"
+				typed = append(typed, logger.String(key, "dup"))
+			}
+
+			log.Infow("Info msg", fields)
+			log.ErrorE("Error msg", 1000, errors.New("failed"), fields)
+			log.ErrorWith("Error msg", 1000, append(typed, logger.Err(errors.New("failed")))...)
+
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if duplicates := duplicateKeys(t, line); len(duplicates) > 0 {
+					t.Errorf("Duplicate keys %v in %s", duplicates, line)
+				}
+			}
+		})
+	}
+}
+
+func registryWith(code uint64) *logger.ErrorCodeRegistry {
+	registry := logger.NewErrorCodeRegistry()
+	_ = registry.Register(logger.ErrorCode{Code: code, Name: "Failed", Category: "test"})
+
+	return registry
+}
+
+func TestWithLabelPolicy_EntryKeys(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil,
+		standardlogger.WithLabelPolicy(logger.DefaultLabelPolicy),
+		standardlogger.WithErrorCodeRegistry(registryWith(1000)),
+	)
+
+	fields := logger.F{"error": "e", "code": "c", "code_name": "n", "category": "g"}
+
+	log.Infow("Info msg", fields)
+	log.Errorw("Unregistered msg", 2000, fields)
+	log.Errorw("Registered msg", 1000, fields)
+	log.ErrorE("Error msg", 2000, errors.New("failed"), fields)
+	log.With(fields).Info("Child msg")
+
+	tests := []struct {
+		msg     string
+		renamed []string
+	}{
+		{"Info msg", nil},
+		{"Unregistered msg", []string{"code"}},
+		{"Registered msg", []string{"code", "code_name", "category"}},
+		{"Error msg", []string{"code", "error"}},
+		{"Child msg", []string{"code", "code_name", "category", "error"}},
+	}
+
+	for _, test := range tests {
+		context := standardlogger.FindByMessage(logs, test.msg)[0].ContextMap()
+
+		for key, val := range fields {
+			renamed := false
+
+			for _, r := range test.renamed {
+				renamed = renamed || r == key
+			}
+
+			if renamed && context["label_"+key] != val || !renamed && context[key] != val {
+				t.Errorf("%s: wrong %s field in %v.", test.msg, key, context)
+			}
+		}
+	}
+}
+
+func TestWithLabelPolicy_FieldPrefix(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(nil,
+		standardlogger.WithLabelPolicy(logger.LabelPolicy{Mode: logger.LabelModeLenient, Prefix: "x_"}),
+	)
+
+	log.Infow("Info msg", logger.F{"ts": "now"})
+	log.InfoWith("Info msg", logger.String("caller", "me"))
+
+	entries := logs.All()
+
+	for i, field := range []zap.Field{zap.String("x_ts", "now"), zap.String("x_caller", "me")} {
+		if !standardlogger.HasField(entries[i], field) {
+			t.Errorf("Field %s missing from %v.", field.Key, entries[i].ContextMap())
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+// SetLevel changes the log level of this logger and all loggers sharing its root.
+func (l *StandardLog) SetLevel(lvl logger.Level) {
+	if l.level == nil {
+		return
+	}
+
+	l.level.SetLevel(getLevelAsZapLevel(lvl))
+}
+
+// GetLevel returns the current log level shared by all loggers created from the same root.
+func (l *StandardLog) GetLevel() logger.Level {
+	if l.level == nil {
+		return logger.LevelInfo
+	}
+
+	return getZapLevelAsLevel(l.level.Level())
+}
+
+// LevelHandler returns http.Handler that reads the current log level on GET
+// and changes it on PUT, both using a JSON payload such as {"level":"debug"}.
+func (l *StandardLog) LevelHandler() http.Handler {
+	return &levelHandler{log: l}
+}
+
+type levelHandler struct {
+	log *StandardLog
+}
+
+type levelPayload struct {
+	Level *logger.Level `json:"level"`
+}
+
+type errorPayload struct {
+	Error string `json:"error"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req levelPayload
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorPayload{Error: err.Error()})
+
+			return
+		}
+
+		if req.Level == nil {
+			writeJSON(w, http.StatusBadRequest, errorPayload{Error: "must specify level"})
+
+			return
+		}
+
+		h.log.SetLevel(*req.Level)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		writeJSON(w, http.StatusMethodNotAllowed, errorPayload{Error: "only GET and PUT are supported"})
+
+		return
+	}
+
+	lvl := h.log.GetLevel()
+	writeJSON(w, http.StatusOK, levelPayload{Level: &lvl})
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload) //nolint:errcheck,errchkjson //nothing to do if the response can't be written
+}
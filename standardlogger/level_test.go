@@ -0,0 +1,102 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestStandardLog_SetLevel(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{})
+	root := log.(*standardlogger.StandardLog) //nolint:forcetypeassert //not necessary in tests.
+	child := log.WithLabels(logger.Labels{"component": "child"})
+
+	child.Debug("hidden")
+
+	root.SetLevel(logger.LevelDebug)
+
+	if root.GetLevel() != logger.LevelDebug {
+		t.Errorf("Wrong level %s, want %s.", root.GetLevel(), logger.LevelDebug)
+	}
+
+	child.Debug("visible")
+
+	root.SetLevel(logger.LevelError)
+	child.Warn("hidden")
+
+	if logs.Len() != 1 || logs.All()[0].Message != "visible" {
+		t.Errorf("Wrong logs %v, want only the visible one.", logs.All())
+	}
+}
+
+func TestStandardLog_SetLevelAffectsNew(t *testing.T) {
+	log := standardlogger.New(logger.Labels{}, standardlogger.WithLogLevel(logger.LevelError))
+	root := log.(*standardlogger.StandardLog) //nolint:forcetypeassert //not necessary in tests.
+
+	core := standardlogger.GetCore(root)
+	if core.Enabled(zapcore.InfoLevel) {
+		t.Error("Info enabled at error level.")
+	}
+
+	root.SetLevel(logger.LevelInfo)
+
+	if !core.Enabled(zapcore.InfoLevel) {
+		t.Error("Info not enabled after SetLevel.")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	log, _ := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithLogLevel(logger.LevelWarn))
+	handler := log.(*standardlogger.StandardLog).LevelHandler() //nolint:forcetypeassert //not necessary in tests.
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"get", http.MethodGet, "", http.StatusOK, `{"level":"warn"}`},
+		{"put", http.MethodPut, `{"level":"debug"}`, http.StatusOK, `{"level":"debug"}`},
+		{"get after put", http.MethodGet, "", http.StatusOK, `{"level":"debug"}`},
+		{"put unknown level", http.MethodPut, `{"level":"verbose"}`, http.StatusBadRequest, `"error"`},
+		{"put missing level", http.MethodPut, `{}`, http.StatusBadRequest, `{"error":"must specify level"}`},
+		{"post", http.MethodPost, `{"level":"info"}`, http.StatusMethodNotAllowed, `"error"`},
+		{"get after errors", http.MethodGet, "", http.StatusOK, `{"level":"debug"}`},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/log/level", strings.NewReader(test.body))
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != test.expectedStatus {
+			t.Errorf("%s: wrong status %d, want %d.", test.name, rec.Code, test.expectedStatus)
+		}
+
+		if !strings.Contains(rec.Body.String(), test.expectedBody) {
+			t.Errorf("%s: wrong body %s, want %s.", test.name, rec.Body.String(), test.expectedBody)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtEncoder renders entries as logfmt key=value pairs:
+// ts, level, caller and msg first, then labels, tags as a comma separated list and other fields.
+// Nested objects and arrays are flattened with dotted keys, e.g. user.id=5 or ids.0=1.
+type logfmtEncoder struct {
+	*zapcore.MapObjectEncoder
+}
+
+func newLogfmtEncoder() *logfmtEncoder {
+	return &logfmtEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+	}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	return e.clone()
+}
+
+func (e *logfmtEncoder) clone() *logfmtEncoder {
+	clone := newLogfmtEncoder()
+	for key, val := range e.Fields {
+		clone.Fields[key] = val
+	}
+
+	return clone
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	buf := bufferPool.Get()
+
+	appendLogfmtPair(buf, "ts", ent.Time.Format(time.RFC3339Nano))
+	appendLogfmtPair(buf, "level", levelName(ent.Level))
+
+	if ent.Caller.Defined {
+		appendLogfmtPair(buf, "caller", ent.Caller.TrimmedPath())
+	}
+
+	appendLogfmtPair(buf, "msg", ent.Message)
+
+	labelKeys, fieldKeys := splitContextKeys(enc.Fields)
+
+	for _, key := range labelKeys {
+		appendLogfmtValue(buf, key, enc.Fields[key])
+	}
+
+	if len(labelKeys) > 0 {
+		appendLogfmtPair(buf, tagsKey, strings.Join(labelKeys, ","))
+	}
+
+	for _, key := range fieldKeys {
+		appendLogfmtValue(buf, key, enc.Fields[key])
+	}
+
+	if ent.Stack != "" {
+		appendLogfmtPair(buf, "stacktrace", ent.Stack)
+	}
+
+	buf.AppendString(zapcore.DefaultLineEnding)
+
+	return buf, nil
+}
+
+// appendLogfmtValue appends val, flattening objects and arrays into dotted keys.
+func appendLogfmtValue(buf *buffer.Buffer, key string, val interface{}) {
+	switch v := normalizeValue(val).(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			appendLogfmtValue(buf, key+"."+k, v[k])
+		}
+	case []interface{}:
+		for i, elem := range v {
+			appendLogfmtValue(buf, key+"."+strconv.Itoa(i), elem)
+		}
+	case string:
+		appendLogfmtPair(buf, key, v)
+	case time.Time:
+		appendLogfmtPair(buf, key, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		appendLogfmtPair(buf, key, v.String())
+	case nil:
+		appendLogfmtPair(buf, key, "null")
+	default:
+		appendLogfmtPair(buf, key, fmt.Sprint(v))
+	}
+}
+
+// normalizeValue turns reflected values, such as logger.Fields or structs, into
+// map[string]interface{} and []interface{} so they can be flattened or converted.
+func normalizeValue(val interface{}) interface{} {
+	switch val.(type) {
+	case nil, string, bool, time.Time, time.Duration, map[string]interface{}, []interface{},
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128:
+		return val
+	}
+
+	out, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprint(val)
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(out, &normalized); err != nil {
+		return string(out)
+	}
+
+	return normalized
+}
+
+func appendLogfmtPair(buf *buffer.Buffer, key, val string) {
+	if buf.Len() > 0 {
+		buf.AppendByte(' ')
+	}
+
+	buf.AppendString(logfmtKey(key))
+	buf.AppendByte('=')
+
+	if needsLogfmtQuoting(val) {
+		buf.AppendString(strconv.Quote(val))
+
+		return
+	}
+
+	buf.AppendString(val)
+}
+
+// logfmtKey replaces characters that are not allowed in logfmt keys with underscores.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+func needsLogfmtQuoting(val string) bool {
+	if val == "" {
+		return true
+	}
+
+	for _, r := range val {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
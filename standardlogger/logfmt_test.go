@@ -0,0 +1,120 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func newLogfmtLogger(labels logger.Labels) (logger.Log, *bytes.Buffer) {
+	var output bytes.Buffer
+
+	return standardlogger.New(labels,
+		standardlogger.WithFormat(standardlogger.FormatLogfmt),
+		standardlogger.WithSingleOutput(&output),
+	), &output
+}
+
+func TestWithFormat_Logfmt(t *testing.T) {
+	log, output := newLogfmtLogger(logger.Labels{"product": "Persistor", "license": "enterprise"})
+
+	log.Infow("Info msg", logger.F{"reqId": 22})
+
+	line := output.String()
+
+	if !strings.HasPrefix(line, "ts=") {
+		t.Errorf("Line %q does not start with ts.", line)
+	}
+
+	wantSubstring := " level=info caller=standardlogger/logfmt_test.go:"
+	if !strings.Contains(line, wantSubstring) {
+		t.Errorf("Line %q missing, want substring '%s'.", line, wantSubstring)
+	}
+
+	wantSuffix := " msg=\"Info msg\" license=enterprise product=Persistor tags=license,product reqId=22\n"
+	if !strings.HasSuffix(line, wantSuffix) {
+		t.Errorf("Line %q, want suffix '%s'.", line, wantSuffix)
+	}
+}
+
+func TestWithFormat_LogfmtQuoting(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"plain", "Persistor", "key0=Persistor"},
+		{"space", "two words", `key0="two words"`},
+		{"equals", "a=b", `key0="a=b"`},
+		{"quote", `say "hi"`, `key0="say \"hi\""`},
+		{"backslash", `C:\logs`, `key0="C:\\logs"`},
+		{"newline", "line1\nline2", `key0="line1\nline2"`},
+		{"empty", "", `key0=""`},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			log, output := newLogfmtLogger(logger.Labels{"key0": test.value})
+			log.Info("msg")
+
+			if !strings.Contains(output.String(), " "+test.expected+" ") {
+				t.Errorf("Line %q missing, want '%s'.", output.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestWithFormat_LogfmtNestedFields(t *testing.T) {
+	log, output := newLogfmtLogger(logger.Labels{})
+
+	log.Infow("msg", logger.F{
+		"user":    logger.F{"id": 5, "address": map[string]interface{}{"city": "Zagreb"}},
+		"ids":     []int{1, 2},
+		"bad key": "value",
+	})
+
+	for _, wantSubstring := range []string{
+		" ids.0=1 ids.1=2 ",
+		" user.address.city=Zagreb user.id=5",
+		" bad_key=value ",
+	} {
+		if !strings.Contains(output.String(), wantSubstring) {
+			t.Errorf("Line %q missing, want substring '%s'.", output.String(), wantSubstring)
+		}
+	}
+}
+
+func TestWithFormat_LogfmtStacktrace(t *testing.T) {
+	log, output := newLogfmtLogger(logger.Labels{})
+
+	log.Error("Error msg", 1000)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Entry %q spans %d lines, want 1.", output.String(), len(lines))
+	}
+
+	for _, wantSubstring := range []string{" code=1000 ", " stacktrace=\""} {
+		if !strings.Contains(lines[0], wantSubstring) {
+			t.Errorf("Line %q missing, want substring '%s'.", lines[0], wantSubstring)
+		}
+	}
+}
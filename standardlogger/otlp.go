@@ -0,0 +1,296 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/standardlogger/otlp"
+)
+
+// WithOTLPExporter returns Option that also sends entries to exporter as OpenTelemetry log records.
+// Labels become resource attributes, fields become record attributes and trace_id, span_id and trace_flags,
+// as added by WithTraceContext, become the trace context of the record.
+// Flush sends the queued records. The logger takes ownership of the exporter and closes it on Close.
+func WithOTLPExporter(exporter *otlp.Exporter) Option {
+	return func(ls *loggerSettings) {
+		ls.otlpExporter = exporter
+		ls.closers = append(ls.closers, exporter)
+	}
+}
+
+// otlpCore converts entries into OpenTelemetry log records and queues them on the exporter.
+type otlpCore struct {
+	zapcore.LevelEnabler
+	exporter *otlp.Exporter
+
+	// context holds all fields added with With, attributes only the ones that aren't labels or tags.
+	context    *zapcore.MapObjectEncoder
+	labels     map[string]string
+	attributes map[string]interface{}
+	resource   *otlp.Resource
+}
+
+func newOTLPCore(exporter *otlp.Exporter, enabler zapcore.LevelEnabler) *otlpCore {
+	core := &otlpCore{
+		LevelEnabler: enabler,
+		exporter:     exporter,
+		context:      zapcore.NewMapObjectEncoder(),
+		labels:       map[string]string{},
+	}
+	core.split()
+
+	return core
+}
+
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &otlpCore{
+		LevelEnabler: c.LevelEnabler,
+		exporter:     c.exporter,
+		context:      zapcore.NewMapObjectEncoder(),
+		labels:       make(map[string]string, len(c.labels)),
+	}
+
+	for key, val := range c.context.Fields {
+		clone.context.Fields[key] = val
+	}
+
+	for key, val := range c.labels {
+		clone.labels[key] = val
+	}
+
+	for _, field := range fields {
+		// profiles nest labels in an object, which would otherwise end up as a single attribute.
+		if labels, ok := field.Interface.(labelsObject); ok {
+			for key, val := range labels {
+				clone.labels[key] = val
+			}
+
+			continue
+		}
+
+		field.AddTo(clone.context)
+	}
+
+	clone.split()
+
+	return clone
+}
+
+// split separates labels from the other context fields and builds the resource.
+func (c *otlpCore) split() {
+	labels := make(map[string]string, len(c.labels))
+	for key, val := range c.labels {
+		labels[key] = val
+	}
+
+	labelKeys, fieldKeys := splitContextKeys(c.context.Fields)
+
+	for _, key := range labelKeys {
+		labels[key] = fmt.Sprint(c.context.Fields[key])
+	}
+
+	c.attributes = make(map[string]interface{}, len(fieldKeys))
+	for _, key := range fieldKeys {
+		c.attributes[key] = c.context.Fields[key]
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	c.resource = &otlp.Resource{Attributes: make([]otlp.KeyValue, 0, len(keys))}
+	for _, key := range keys {
+		c.resource.Attributes = append(c.resource.Attributes, otlp.KeyValue{Key: key, Value: otlp.String(labels[key])})
+	}
+}
+
+func (c *otlpCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *otlpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for key, val := range c.attributes {
+		enc.Fields[key] = val
+	}
+
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	record := otlp.LogRecord{
+		TimeUnixNano:         uint64(ent.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+		SeverityNumber:       otlpSeverity(ent.Level),
+		SeverityText:         levelName(ent.Level),
+		Body:                 otlp.String(ent.Message),
+	}
+
+	record.TraceID, _ = enc.Fields[traceIDKey].(string)
+	record.SpanID, _ = enc.Fields[spanIDKey].(string)
+
+	if flags, ok := enc.Fields[traceFlagsKey].(string); ok {
+		parsed, err := strconv.ParseUint(flags, 16, 32)
+		if err == nil {
+			record.Flags = uint32(parsed)
+		}
+	}
+
+	delete(enc.Fields, traceIDKey)
+	delete(enc.Fields, spanIDKey)
+	delete(enc.Fields, traceFlagsKey)
+
+	record.Attributes = otlpAttributes(enc.Fields)
+
+	// semantic conventions for the source code and exception attributes.
+	if ent.Caller.Defined {
+		record.Attributes = append(record.Attributes,
+			otlp.KeyValue{Key: "code.filepath", Value: otlp.String(ent.Caller.File)},
+			otlp.KeyValue{Key: "code.lineno", Value: otlp.Int(int64(ent.Caller.Line))},
+		)
+
+		if ent.Caller.Function != "" {
+			record.Attributes = append(record.Attributes, otlp.KeyValue{Key: "code.function", Value: otlp.String(ent.Caller.Function)})
+		}
+	}
+
+	if ent.Stack != "" {
+		record.Attributes = append(record.Attributes, otlp.KeyValue{Key: "exception.stacktrace", Value: otlp.String(ent.Stack)})
+	}
+
+	c.exporter.Export(c.resource, record)
+
+	// panic and fatal entries are sent right away, since the process is about to stop.
+	// Failed exports aren't retried, so an unavailable endpoint doesn't hold up the exit.
+	if ent.Level > zapcore.ErrorLevel {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		return c.exporter.SyncContext(ctx) //nolint:wrapcheck //returned as is to satisfy zapcore.Core
+	}
+
+	return nil
+}
+
+func (c *otlpCore) Sync() error {
+	return c.exporter.Sync() //nolint:wrapcheck //returned as is to satisfy zapcore.Core
+}
+
+func otlpSeverity(lvl zapcore.Level) otlp.SeverityNumber {
+	switch {
+	case lvl <= traceLevel:
+		return otlp.SeverityTrace
+	case lvl == zapcore.DebugLevel:
+		return otlp.SeverityDebug
+	case lvl == zapcore.InfoLevel:
+		return otlp.SeverityInfo
+	case lvl == zapcore.WarnLevel:
+		return otlp.SeverityWarn
+	case lvl == zapcore.ErrorLevel:
+		return otlp.SeverityError
+	default:
+		return otlp.SeverityFatal
+	}
+}
+
+// otlpAttributes converts fields into attributes sorted by key.
+func otlpAttributes(fields map[string]interface{}) []otlp.KeyValue {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	attributes := make([]otlp.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, otlp.KeyValue{Key: key, Value: otlpValue(fields[key])})
+	}
+
+	return attributes
+}
+
+func otlpValue(val interface{}) otlp.Value {
+	switch v := normalizeValue(val).(type) {
+	case nil:
+		return otlp.Value{}
+	case string:
+		return otlp.String(v)
+	case bool:
+		return otlp.Bool(v)
+	case int:
+		return otlp.Int(int64(v))
+	case int8:
+		return otlp.Int(int64(v))
+	case int16:
+		return otlp.Int(int64(v))
+	case int32:
+		return otlp.Int(int64(v))
+	case int64:
+		return otlp.Int(v)
+	case uint8:
+		return otlp.Int(int64(v))
+	case uint16:
+		return otlp.Int(int64(v))
+	case uint32:
+		return otlp.Int(int64(v))
+	case uint, uint64, uintptr:
+		return otlpUint(v)
+	case float32:
+		return otlp.Double(float64(v))
+	case float64:
+		return otlp.Double(v)
+	case time.Time:
+		return otlp.String(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return otlp.String(v.String())
+	case map[string]interface{}:
+		return otlp.Map(otlpAttributes(v)...)
+	case []interface{}:
+		values := make([]otlp.Value, 0, len(v))
+		for _, elem := range v {
+			values = append(values, otlpValue(elem))
+		}
+
+		return otlp.Array(values...)
+	default:
+		return otlp.String(fmt.Sprint(v))
+	}
+}
+
+// otlpUint converts unsigned integers, falling back to a string for values that don't fit into int64.
+func otlpUint(val interface{}) otlp.Value {
+	parsed, err := strconv.ParseUint(fmt.Sprint(val), 10, 64)
+	if err != nil || parsed > math.MaxInt64 {
+		return otlp.String(fmt.Sprint(val))
+	}
+
+	return otlp.Int(int64(parsed))
+}
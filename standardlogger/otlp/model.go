@@ -0,0 +1,122 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+// SeverityNumber is the OpenTelemetry log severity, see https://opentelemetry.io/docs/specs/otel/logs/data-model/#field-severitynumber.
+type SeverityNumber int32
+
+const (
+	SeverityTrace SeverityNumber = 1
+	SeverityDebug SeverityNumber = 5
+	SeverityInfo  SeverityNumber = 9
+	SeverityWarn  SeverityNumber = 13
+	SeverityError SeverityNumber = 17
+	SeverityFatal SeverityNumber = 21
+)
+
+// LogRecord is an OpenTelemetry log record in the OTLP/JSON encoding.
+type LogRecord struct {
+	TimeUnixNano         uint64         `json:"timeUnixNano,string"`
+	ObservedTimeUnixNano uint64         `json:"observedTimeUnixNano,string"`
+	SeverityNumber       SeverityNumber `json:"severityNumber"`
+	SeverityText         string         `json:"severityText,omitempty"`
+	Body                 Value          `json:"body"`
+	Attributes           []KeyValue     `json:"attributes,omitempty"`
+	// TraceID and SpanID are hex encoded, as in the W3C traceparent header.
+	TraceID string `json:"traceId,omitempty"`
+	SpanID  string `json:"spanId,omitempty"`
+	Flags   uint32 `json:"flags,omitempty"`
+}
+
+// Resource describes the entity producing log records.
+type Resource struct {
+	Attributes []KeyValue `json:"attributes"`
+}
+
+// KeyValue is an attribute of a LogRecord or Resource.
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value Value  `json:"value"`
+}
+
+// Value is an OpenTelemetry AnyValue, only one of its fields is set.
+// The zero Value is an empty value.
+type Value struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    *int64       `json:"intValue,string,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	ArrayValue  *ArrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *KvlistValue `json:"kvlistValue,omitempty"`
+}
+
+// ArrayValue is a list of values.
+type ArrayValue struct {
+	Values []Value `json:"values"`
+}
+
+// KvlistValue is a list of key value pairs.
+type KvlistValue struct {
+	Values []KeyValue `json:"values"`
+}
+
+// String returns a string Value.
+func String(v string) Value {
+	return Value{StringValue: &v}
+}
+
+// Bool returns a bool Value.
+func Bool(v bool) Value {
+	return Value{BoolValue: &v}
+}
+
+// Int returns an integer Value.
+func Int(v int64) Value {
+	return Value{IntValue: &v}
+}
+
+// Double returns a floating point Value.
+func Double(v float64) Value {
+	return Value{DoubleValue: &v}
+}
+
+// Array returns an array Value.
+func Array(values ...Value) Value {
+	return Value{ArrayValue: &ArrayValue{Values: values}}
+}
+
+// Map returns a key value list Value.
+func Map(values ...KeyValue) Value {
+	return Value{KvlistValue: &KvlistValue{Values: values}}
+}
+
+// exportLogsServiceRequest is the body of an OTLP/HTTP logs export.
+type exportLogsServiceRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  Resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type scopeLogs struct {
+	Scope      instrumentationScope `json:"scope"`
+	LogRecords []LogRecord          `json:"logRecords"`
+}
+
+type instrumentationScope struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,565 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp provides an exporter that batches OpenTelemetry log records and sends them to an OTLP/HTTP endpoint,
+// retrying failed exports with exponential backoff.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultEndpoint is the logs endpoint of a collector running on the same host.
+	DefaultEndpoint = "http://localhost:4318/v1/logs"
+
+	scopeName = "github.com/dataphos/lib-logger"
+
+	defaultBatchSize     = 512
+	defaultMaxQueueSize  = 2048
+	defaultFlushInterval = time.Second
+	defaultTimeout       = 10 * time.Second
+	defaultRetryInitial  = 5 * time.Second
+	defaultRetryMax      = 30 * time.Second
+	defaultRetryElapsed  = time.Minute
+
+	maxErrorBodySize = 1024
+)
+
+var (
+	// ErrClosed is returned when syncing a closed Exporter.
+	ErrClosed = errors.New("otlp exporter closed")
+	// ErrExport is returned when the endpoint rejects an export.
+	ErrExport = errors.New("otlp export failed")
+)
+
+// Exporter batches log records and sends them to an OTLP/HTTP endpoint in the background.
+// Records are sent when a batch fills up, every flush interval and on Sync and Close.
+// Records exported while the queue is full or after Close are dropped.
+type Exporter struct {
+	endpoint string
+	settings exporterSettings
+
+	mu      sync.Mutex
+	queue   []queued
+	closed  bool
+	dropped uint64
+
+	// sendMu serializes sending, so records are sent in the order they were exported.
+	sendMu sync.Mutex
+
+	// abort is closed by a flush with a done ctx, so the flush in progress stops retrying and leaves it the records.
+	// It is replaced once that flush takes over sending.
+	abortMu sync.Mutex
+	abort   chan struct{}
+
+	kick chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type queued struct {
+	resource *Resource
+	record   LogRecord
+}
+
+type Option func(*exporterSettings)
+
+type exporterSettings struct {
+	client        *http.Client
+	headers       map[string]string
+	resource      []KeyValue
+	batchSize     int
+	maxQueueSize  int
+	flushInterval time.Duration
+	retry         retrySettings
+	errorHandler  func(error)
+}
+
+type retrySettings struct {
+	enabled     bool
+	initial     time.Duration
+	maxInterval time.Duration
+	maxElapsed  time.Duration
+}
+
+var defaultSettings = exporterSettings{
+	client:        &http.Client{Timeout: defaultTimeout},
+	batchSize:     defaultBatchSize,
+	maxQueueSize:  defaultMaxQueueSize,
+	flushInterval: defaultFlushInterval,
+	retry: retrySettings{
+		enabled:     true,
+		initial:     defaultRetryInitial,
+		maxInterval: defaultRetryMax,
+		maxElapsed:  defaultRetryElapsed,
+	},
+	errorHandler: func(err error) {
+		fmt.Fprintf(os.Stderr, "otlp: %v\n", err)
+	},
+}
+
+// WithHTTPClient returns Option that sends exports with client.
+// The default client times out after 10 seconds.
+func WithHTTPClient(client *http.Client) Option {
+	return func(es *exporterSettings) {
+		es.client = client
+	}
+}
+
+// WithHeaders returns Option that adds headers to every export, e.g. for authentication.
+func WithHeaders(headers map[string]string) Option {
+	return func(es *exporterSettings) {
+		es.headers = headers
+	}
+}
+
+// WithResourceAttributes returns Option that adds attributes, such as service.name, to the resource of every record.
+func WithResourceAttributes(attributes ...KeyValue) Option {
+	return func(es *exporterSettings) {
+		es.resource = append(es.resource, attributes...)
+	}
+}
+
+// WithBatchSize returns Option that sends records as soon as batchSize of them are queued.
+func WithBatchSize(batchSize int) Option {
+	return func(es *exporterSettings) {
+		es.batchSize = batchSize
+	}
+}
+
+// WithMaxQueueSize returns Option that limits the number of queued records.
+func WithMaxQueueSize(maxQueueSize int) Option {
+	return func(es *exporterSettings) {
+		es.maxQueueSize = maxQueueSize
+	}
+}
+
+// WithFlushInterval returns Option that sets how often queued records are sent.
+// Intervals that aren't positive are ignored and the default of 1 second is kept.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(es *exporterSettings) {
+		if interval > 0 {
+			es.flushInterval = interval
+		}
+	}
+}
+
+// WithRetry returns Option that sets the backoff of retried exports.
+// The first retry waits about initial and every next one twice as long, up to maxInterval.
+// An export is abandoned once retrying it would take longer than maxElapsed.
+func WithRetry(initial, maxInterval, maxElapsed time.Duration) Option {
+	return func(es *exporterSettings) {
+		es.retry = retrySettings{
+			enabled:     true,
+			initial:     initial,
+			maxInterval: maxInterval,
+			maxElapsed:  maxElapsed,
+		}
+	}
+}
+
+// WithoutRetry returns Option that drops records whose export failed.
+func WithoutRetry() Option {
+	return func(es *exporterSettings) {
+		es.retry.enabled = false
+	}
+}
+
+// WithErrorHandler returns Option that sets the handler of errors of background exports.
+// By default, they are written to stderr.
+func WithErrorHandler(handler func(error)) Option {
+	return func(es *exporterSettings) {
+		es.errorHandler = handler
+	}
+}
+
+// New returns an Exporter that sends records to endpoint, the full URL of the logs endpoint, such as DefaultEndpoint.
+func New(endpoint string, opts ...Option) *Exporter {
+	settings := defaultSettings
+
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	exporter := &Exporter{
+		endpoint: endpoint,
+		settings: settings,
+		kick:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		abort:    make(chan struct{}),
+	}
+
+	exporter.wg.Add(1)
+
+	go exporter.run()
+
+	return exporter
+}
+
+// Export queues the record for sending.
+// Records with the same *Resource are sent together, so the resource must not be modified afterwards.
+func (e *Exporter) Export(resource *Resource, record LogRecord) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed || len(e.queue) >= e.settings.maxQueueSize {
+		atomic.AddUint64(&e.dropped, 1)
+
+		return
+	}
+
+	e.queue = append(e.queue, queued{resource: resource, record: record})
+
+	if len(e.queue) >= e.settings.batchSize {
+		select {
+		case e.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of records that were dropped, either because the queue was full,
+// their export failed or they were exported after Close.
+func (e *Exporter) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+// Sync sends all queued records and returns the error of the export, if any.
+func (e *Exporter) Sync() error {
+	return e.SyncContext(context.Background())
+}
+
+// SyncContext is like Sync, but stops retrying failed exports once ctx is done or the Exporter is closed.
+// The export that was waiting to be retried is then attempted once more, right away.
+// If ctx is already done, every export is attempted only once, and a background export waiting to be retried
+// is abandoned, so its records are attempted once by this call instead.
+func (e *Exporter) SyncContext(ctx context.Context) error {
+	e.mu.Lock()
+	closed := e.closed
+	e.mu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	return e.flush(ctx)
+}
+
+// Close stops the background exports and sends the queued records.
+// Exports waiting to be retried, including those of Sync, are attempted once more and the queued records only once,
+// so Close doesn't wait for an unavailable endpoint. Closing an already closed Exporter does nothing.
+func (e *Exporter) Close() error {
+	e.mu.Lock()
+
+	if e.closed {
+		e.mu.Unlock()
+
+		return nil
+	}
+
+	e.closed = true
+	close(e.done)
+	e.mu.Unlock()
+
+	e.wg.Wait()
+
+	return e.flush(context.Background())
+}
+
+func (e *Exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.settings.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		case <-e.kick:
+		}
+
+		if err := e.flush(context.Background()); err != nil {
+			e.settings.errorHandler(err)
+		}
+	}
+}
+
+// flush sends the queued records in batches and returns the error of the first batch that failed.
+// Records of a failed batch are dropped and the remaining batches are still sent.
+// If it is aborted by a flush with a done ctx, it puts the records it didn't send back at the front of the queue.
+func (e *Exporter) flush(ctx context.Context) error {
+	urgent := ctx.Err() != nil
+	if urgent {
+		e.abortRetries()
+	}
+
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+
+	abort := e.abortChannel(urgent)
+
+	e.mu.Lock()
+	queue := e.queue
+	e.queue = nil
+	e.mu.Unlock()
+
+	var err error
+
+	for len(queue) > 0 {
+		if isClosed(abort) {
+			e.requeue(queue)
+
+			return err
+		}
+
+		size := len(queue)
+		if e.settings.batchSize > 0 && size > e.settings.batchSize {
+			size = e.settings.batchSize
+		}
+
+		if sendErr := e.send(ctx, abort, queue[:size]); sendErr != nil {
+			if isClosed(abort) {
+				e.requeue(queue)
+
+				return err
+			}
+
+			atomic.AddUint64(&e.dropped, uint64(size))
+
+			if err == nil {
+				err = sendErr
+			}
+		}
+
+		queue = queue[size:]
+	}
+
+	return err
+}
+
+// abortRetries aborts the flush in progress, if any.
+func (e *Exporter) abortRetries() {
+	e.abortMu.Lock()
+	defer e.abortMu.Unlock()
+
+	if !isClosed(e.abort) {
+		close(e.abort)
+	}
+}
+
+// abortChannel returns the channel that aborts the flush holding sendMu, replacing an already closed one
+// if the flush is the one that closed it.
+func (e *Exporter) abortChannel(urgent bool) <-chan struct{} {
+	e.abortMu.Lock()
+	defer e.abortMu.Unlock()
+
+	if urgent && isClosed(e.abort) {
+		e.abort = make(chan struct{})
+	}
+
+	return e.abort
+}
+
+// requeue puts records back at the front of the queue.
+func (e *Exporter) requeue(records []queued) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.queue = append(records[:len(records):len(records)], e.queue...)
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// send posts the batch, retrying failed exports until ctx is done or the Exporter is closed.
+// Once abort is closed, it returns the error of the last attempt without retrying.
+func (e *Exporter) send(ctx context.Context, abort <-chan struct{}, batch []queued) error {
+	body, err := json.Marshal(e.newRequest(batch))
+	if err != nil {
+		return fmt.Errorf("encoding export request: %w", err)
+	}
+
+	retry := e.settings.retry.enabled
+	backoff := e.settings.retry.initial
+	deadline := time.Now().Add(e.settings.retry.maxElapsed)
+
+	for {
+		retryAfter, err := e.post(body)
+		if err == nil {
+			return nil
+		}
+
+		if retryAfter < 0 || !retry || e.stopped(ctx, abort) {
+			return err
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			// full jitter between half and the whole backoff.
+			wait = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)) //nolint:gosec //jitter doesn't need a secure source
+		}
+
+		if time.Now().Add(wait).After(deadline) {
+			return err
+		}
+
+		if !e.wait(ctx, abort, wait) {
+			if isClosed(abort) {
+				return err
+			}
+
+			// stopped while waiting, the next attempt is the last one.
+			retry = false
+		}
+
+		backoff *= 2
+		if backoff > e.settings.retry.maxInterval {
+			backoff = e.settings.retry.maxInterval
+		}
+	}
+}
+
+// wait waits for d and reports whether it did so without ctx being done, abort being closed or the Exporter being closed.
+func (e *Exporter) wait(ctx context.Context, abort <-chan struct{}, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-abort:
+		return false
+	case <-e.done:
+		return false
+	}
+}
+
+// stopped reports whether ctx is done, abort is closed or the Exporter is closed.
+func (e *Exporter) stopped(ctx context.Context, abort <-chan struct{}) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-abort:
+		return true
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// post sends a single export request.
+// If the export can be retried, it returns how long the endpoint asked to wait, or zero; otherwise it returns -1.
+func (e *Exporter) post(body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return -1, fmt.Errorf("creating export request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range e.settings.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.settings.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending export request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck //draining to reuse the connection
+
+		return 0, nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("%w: %s: %s", ErrExport, resp.Status, bytes.TrimSpace(message))
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), err
+	default:
+		return -1, err
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// newRequest groups the batch by resource, keeping the order of records.
+func (e *Exporter) newRequest(batch []queued) exportLogsServiceRequest {
+	var request exportLogsServiceRequest
+
+	index := make(map[*Resource]int)
+
+	for _, q := range batch {
+		i, ok := index[q.resource]
+		if !ok {
+			i = len(request.ResourceLogs)
+			index[q.resource] = i
+
+			request.ResourceLogs = append(request.ResourceLogs, resourceLogs{
+				Resource:  e.resource(q.resource),
+				ScopeLogs: []scopeLogs{{Scope: instrumentationScope{Name: scopeName}}},
+			})
+		}
+
+		scope := &request.ResourceLogs[i].ScopeLogs[0]
+		scope.LogRecords = append(scope.LogRecords, q.record)
+	}
+
+	return request
+}
+
+func (e *Exporter) resource(resource *Resource) Resource {
+	attributes := make([]KeyValue, 0, len(e.settings.resource))
+	attributes = append(attributes, e.settings.resource...)
+
+	if resource != nil {
+		attributes = append(attributes, resource.Attributes...)
+	}
+
+	return Resource{Attributes: attributes}
+}
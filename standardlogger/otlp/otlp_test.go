@@ -0,0 +1,465 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/standardlogger/otlp"
+)
+
+type exportRequest struct {
+	ResourceLogs []struct {
+		Resource  otlp.Resource `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []otlp.LogRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// collector is a stand-in OTLP/HTTP collector that answers with the given statuses in order and 200 after them.
+type collector struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []exportRequest
+	headers  []http.Header
+	received chan struct{}
+}
+
+func newCollector(t *testing.T, statuses ...int) *collector {
+	t.Helper()
+
+	c := &collector{statuses: statuses, received: make(chan struct{}, 100)}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if len(c.statuses) > 0 {
+			status := c.statuses[0]
+			c.statuses = c.statuses[1:]
+
+			if status != http.StatusOK {
+				http.Error(w, "unavailable", status)
+				c.headers = append(c.headers, r.Header)
+
+				return
+			}
+		}
+
+		var request exportRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Decoding request failed: %v.", err)
+		}
+
+		c.requests = append(c.requests, request)
+		c.headers = append(c.headers, r.Header)
+		c.received <- struct{}{}
+	}))
+	t.Cleanup(c.Close)
+
+	return c
+}
+
+func (c *collector) Requests() []exportRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]exportRequest(nil), c.requests...)
+}
+
+func (c *collector) Attempts() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.headers)
+}
+
+func record(body string) otlp.LogRecord {
+	return otlp.LogRecord{
+		TimeUnixNano:   uint64(time.Now().UnixNano()),
+		SeverityNumber: otlp.SeverityInfo,
+		SeverityText:   "info",
+		Body:           otlp.String(body),
+		Attributes:     []otlp.KeyValue{{Key: "count", Value: otlp.Int(5)}},
+	}
+}
+
+func resource(product string) *otlp.Resource {
+	return &otlp.Resource{Attributes: []otlp.KeyValue{{Key: "product", Value: otlp.String(product)}}}
+}
+
+func TestExporter_Sync(t *testing.T) {
+	c := newCollector(t)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithHeaders(map[string]string{"Authorization": "Bearer token"}),
+		otlp.WithResourceAttributes(otlp.KeyValue{Key: "service.name", Value: otlp.String("persistor")}),
+		otlp.WithFlushInterval(time.Hour),
+	)
+	defer exporter.Close()
+
+	persistor, indexer := resource("Persistor"), resource("Indexer")
+
+	exporter.Export(persistor, record("first"))
+	exporter.Export(indexer, record("second"))
+	exporter.Export(persistor, record("third"))
+
+	if err := exporter.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v.", err)
+	}
+
+	requests := c.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d.", len(requests))
+	}
+
+	if c.headers[0].Get("Content-Type") != "application/json" || c.headers[0].Get("Authorization") != "Bearer token" {
+		t.Errorf("Wrong headers %v.", c.headers[0])
+	}
+
+	resourceLogs := requests[0].ResourceLogs
+	if len(resourceLogs) != 2 {
+		t.Fatalf("Expected 2 resources, got %d.", len(resourceLogs))
+	}
+
+	attributes := resourceLogs[0].Resource.Attributes
+	if len(attributes) != 2 || attributes[0].Key != "service.name" || *attributes[1].Value.StringValue != "Persistor" {
+		t.Errorf("Wrong resource attributes %+v.", attributes)
+	}
+
+	records := resourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 2 || *records[0].Body.StringValue != "first" || *records[1].Body.StringValue != "third" {
+		t.Errorf("Wrong records %+v.", records)
+	}
+
+	if *records[0].Attributes[0].Value.IntValue != 5 {
+		t.Errorf("Wrong attributes %+v.", records[0].Attributes)
+	}
+
+	if resourceLogs[0].ScopeLogs[0].Scope.Name == "" {
+		t.Error("Scope name missing.")
+	}
+}
+
+func TestExporter_BatchSize(t *testing.T) {
+	c := newCollector(t)
+
+	exporter := otlp.New(c.URL, otlp.WithBatchSize(2), otlp.WithFlushInterval(time.Hour))
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+	exporter.Export(nil, record("second"))
+
+	select {
+	case <-c.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Full batch wasn't sent.")
+	}
+
+	records := c.Requests()[0].ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 2 {
+		t.Errorf("Expected 2 records, got %d.", len(records))
+	}
+}
+
+func TestExporter_FlushInterval(t *testing.T) {
+	c := newCollector(t)
+
+	exporter := otlp.New(c.URL, otlp.WithFlushInterval(10*time.Millisecond))
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+
+	select {
+	case <-c.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Records weren't sent after the flush interval.")
+	}
+}
+
+func TestExporter_InvalidFlushInterval(t *testing.T) {
+	c := newCollector(t)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		exporter := otlp.New(c.URL, otlp.WithFlushInterval(interval))
+
+		exporter.Export(nil, record("first"))
+
+		select {
+		case <-c.received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Records weren't sent after the default flush interval.")
+		}
+
+		if err := exporter.Close(); err != nil {
+			t.Errorf("Close failed: %v.", err)
+		}
+	}
+}
+
+func TestExporter_Retry(t *testing.T) {
+	c := newCollector(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithRetry(time.Millisecond, 10*time.Millisecond, 5*time.Second),
+	)
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+
+	if err := exporter.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v.", err)
+	}
+
+	if c.Attempts() != 3 {
+		t.Errorf("Expected 3 attempts, got %d.", c.Attempts())
+	}
+
+	if len(c.Requests()) != 1 || exporter.Dropped() != 0 {
+		t.Errorf("Record wasn't delivered, dropped %d.", exporter.Dropped())
+	}
+}
+
+func TestExporter_RetryGivesUp(t *testing.T) {
+	statuses := make([]int, 100)
+	for i := range statuses {
+		statuses[i] = http.StatusBadGateway
+	}
+
+	c := newCollector(t, statuses...)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithRetry(10*time.Millisecond, 10*time.Millisecond, 25*time.Millisecond),
+	)
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+
+	if err := exporter.Sync(); !errors.Is(err, otlp.ErrExport) {
+		t.Errorf("Expected ErrExport, got %v.", err)
+	}
+
+	if c.Attempts() < 2 {
+		t.Errorf("Expected retries, got %d attempts.", c.Attempts())
+	}
+
+	if exporter.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped record, got %d.", exporter.Dropped())
+	}
+}
+
+func TestExporter_NoRetryOnBadRequest(t *testing.T) {
+	c := newCollector(t, http.StatusBadRequest)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithRetry(time.Millisecond, time.Millisecond, 5*time.Second),
+	)
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+	exporter.Export(nil, record("second"))
+
+	if err := exporter.Sync(); !errors.Is(err, otlp.ErrExport) {
+		t.Errorf("Expected ErrExport, got %v.", err)
+	}
+
+	if c.Attempts() != 1 {
+		t.Errorf("Expected 1 attempt, got %d.", c.Attempts())
+	}
+
+	if exporter.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped records, got %d.", exporter.Dropped())
+	}
+}
+
+func TestExporter_SendsBatchesAfterFailure(t *testing.T) {
+	c := newCollector(t, http.StatusBadRequest)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithBatchSize(1),
+	)
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+	exporter.Export(nil, record("second"))
+
+	if err := exporter.Sync(); !errors.Is(err, otlp.ErrExport) {
+		t.Errorf("Expected ErrExport, got %v.", err)
+	}
+
+	requests := c.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected the second batch to be delivered, got %d requests.", len(requests))
+	}
+
+	if records := requests[0].ResourceLogs[0].ScopeLogs[0].LogRecords; *records[0].Body.StringValue != "second" {
+		t.Errorf("Wrong record %s, want second.", *records[0].Body.StringValue)
+	}
+
+	if exporter.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped record, got %d.", exporter.Dropped())
+	}
+}
+
+func TestExporter_MaxQueueSize(t *testing.T) {
+	c := newCollector(t)
+
+	exporter := otlp.New(c.URL, otlp.WithMaxQueueSize(1), otlp.WithFlushInterval(time.Hour))
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+	exporter.Export(nil, record("second"))
+
+	if exporter.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped record, got %d.", exporter.Dropped())
+	}
+}
+
+func TestExporter_Close(t *testing.T) {
+	c := newCollector(t)
+
+	exporter := otlp.New(c.URL, otlp.WithFlushInterval(time.Hour))
+
+	exporter.Export(nil, record("first"))
+
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("Close failed: %v.", err)
+	}
+
+	if len(c.Requests()) != 1 {
+		t.Errorf("Queued records weren't sent on Close.")
+	}
+
+	if err := exporter.Close(); err != nil {
+		t.Errorf("Second Close failed: %v.", err)
+	}
+
+	if err := exporter.Sync(); !errors.Is(err, otlp.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v.", err)
+	}
+
+	exporter.Export(nil, record("second"))
+
+	if exporter.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped record, got %d.", exporter.Dropped())
+	}
+}
+
+func unavailable(n int) []int {
+	statuses := make([]int, n)
+	for i := range statuses {
+		statuses[i] = http.StatusServiceUnavailable
+	}
+
+	return statuses
+}
+
+func TestExporter_CloseStopsRetries(t *testing.T) {
+	c := newCollector(t, unavailable(1000)...)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithRetry(50*time.Millisecond, 50*time.Millisecond, time.Minute),
+	)
+
+	exporter.Export(nil, record("first"))
+
+	synced := make(chan error, 1)
+
+	go func() {
+		synced <- exporter.Sync()
+	}()
+
+	for c.Attempts() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+
+	exporter.Export(nil, record("second"))
+
+	if err := exporter.Close(); !errors.Is(err, otlp.ErrExport) {
+		t.Errorf("Expected ErrExport, got %v.", err)
+	}
+
+	select {
+	case err := <-synced:
+		if !errors.Is(err, otlp.ErrExport) {
+			t.Errorf("Expected ErrExport, got %v.", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync kept retrying after Close.")
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close took %v.", elapsed)
+	}
+
+	if exporter.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped records, got %d.", exporter.Dropped())
+	}
+}
+
+func TestExporter_SyncContext(t *testing.T) {
+	c := newCollector(t, unavailable(1000)...)
+
+	exporter := otlp.New(c.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithRetry(time.Millisecond, time.Millisecond, time.Minute),
+	)
+	defer exporter.Close()
+
+	exporter.Export(nil, record("first"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := exporter.SyncContext(ctx); !errors.Is(err, otlp.ErrExport) {
+		t.Errorf("Expected ErrExport, got %v.", err)
+	}
+
+	if c.Attempts() != 1 {
+		t.Errorf("Expected 1 attempt, got %d.", c.Attempts())
+	}
+
+	exporter.Export(nil, record("second"))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := exporter.SyncContext(ctx); !errors.Is(err, otlp.ErrExport) {
+		t.Errorf("Expected ErrExport, got %v.", err)
+	}
+
+	if c.Attempts() < 3 {
+		t.Errorf("Expected retries until the context was done, got %d attempts.", c.Attempts())
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+	"github.com/dataphos/lib-logger/standardlogger/otlp"
+)
+
+type otlpResourceLogs struct {
+	Resource  otlp.Resource `json:"resource"`
+	ScopeLogs []struct {
+		LogRecords []otlp.LogRecord `json:"logRecords"`
+	} `json:"scopeLogs"`
+}
+
+// exportOTLP logs with a logger exporting to a stand-in collector and returns the received resource logs.
+func exportOTLP(t *testing.T, log func(logger.Log), opts ...standardlogger.Option) []otlpResourceLogs {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		received []otlpResourceLogs
+	)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Decoding request failed: %v.", err)
+		}
+
+		mu.Lock()
+		received = append(received, request.ResourceLogs...)
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	exporter := otlp.New(collector.URL, otlp.WithFlushInterval(time.Hour))
+	defer exporter.Close()
+
+	opts = append([]standardlogger.Option{
+		standardlogger.WithSingleOutput(io.Discard),
+		standardlogger.WithOTLPExporter(exporter),
+	}, opts...)
+	log(standardlogger.New(logger.Labels{"product": "Persistor"}, opts...))
+
+	if err := exporter.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v.", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return received
+}
+
+func otlpAttribute(attributes []otlp.KeyValue, key string) (otlp.Value, bool) {
+	for _, attribute := range attributes {
+		if attribute.Key == key {
+			return attribute.Value, true
+		}
+	}
+
+	return otlp.Value{}, false
+}
+
+func TestWithOTLPExporter(t *testing.T) {
+	resourceLogs := exportOTLP(t, func(log logger.Log) {
+		log.WithLabels(logger.Labels{"component": "writer"}).Errorw("Error msg", 1000, logger.F{"id": "1", "count": 3})
+	})
+
+	if len(resourceLogs) != 1 || len(resourceLogs[0].ScopeLogs) != 1 || len(resourceLogs[0].ScopeLogs[0].LogRecords) != 1 {
+		t.Fatalf("Expected a single record, got %+v.", resourceLogs)
+	}
+
+	resource := resourceLogs[0].Resource.Attributes
+	if len(resource) != 2 || resource[0].Key != "component" || resource[1].Key != "product" || *resource[1].Value.StringValue != "Persistor" {
+		t.Errorf("Wrong resource attributes %+v.", resource)
+	}
+
+	record := resourceLogs[0].ScopeLogs[0].LogRecords[0]
+
+	if record.SeverityNumber != otlp.SeverityError || record.SeverityText != "error" {
+		t.Errorf("Wrong severity %d %s.", record.SeverityNumber, record.SeverityText)
+	}
+
+	if *record.Body.StringValue != "Error msg" || record.TimeUnixNano == 0 {
+		t.Errorf("Wrong record %+v.", record)
+	}
+
+	if value, ok := otlpAttribute(record.Attributes, "code"); !ok || *value.IntValue != 1000 {
+		t.Errorf("Wrong code attribute %+v.", value)
+	}
+
+	if value, ok := otlpAttribute(record.Attributes, "id"); !ok || *value.StringValue != "1" {
+		t.Errorf("Wrong id attribute %+v.", value)
+	}
+
+	if value, ok := otlpAttribute(record.Attributes, "count"); !ok || *value.IntValue != 3 {
+		t.Errorf("Wrong count attribute %+v.", value)
+	}
+
+	for _, key := range []string{"code.filepath", "code.lineno", "exception.stacktrace"} {
+		if _, ok := otlpAttribute(record.Attributes, key); !ok {
+			t.Errorf("Attribute %s missing.", key)
+		}
+	}
+
+	for _, key := range []string{"product", "component", "tags"} {
+		if _, ok := otlpAttribute(record.Attributes, key); ok {
+			t.Errorf("Attribute %s present.", key)
+		}
+	}
+}
+
+func TestWithOTLPExporter_TraceContext(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	resourceLogs := exportOTLP(t, func(log logger.Log) {
+		log.WithContext(ctx).Info("Info msg")
+	}, standardlogger.WithTraceContext())
+
+	record := resourceLogs[0].ScopeLogs[0].LogRecords[0]
+
+	if record.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || record.SpanID != "00f067aa0ba902b7" || record.Flags != 1 {
+		t.Errorf("Wrong trace context %s %s %d.", record.TraceID, record.SpanID, record.Flags)
+	}
+
+	if _, ok := otlpAttribute(record.Attributes, "trace_id"); ok {
+		t.Error("Attribute trace_id present.")
+	}
+}
+
+func TestWithOTLPExporter_Level(t *testing.T) {
+	resourceLogs := exportOTLP(t, func(log logger.Log) {
+		log.Debug("Debug msg")
+		log.Warn("Warn msg")
+	})
+
+	records := resourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 || records[0].SeverityNumber != otlp.SeverityWarn {
+		t.Errorf("Expected only the warning, got %+v.", records)
+	}
+}
+
+func TestWithOTLPExporter_Profile(t *testing.T) {
+	resourceLogs := exportOTLP(t, func(log logger.Log) {
+		log.Info("Info msg")
+	}, standardlogger.WithFormat(standardlogger.FormatJSON), standardlogger.WithProfile(standardlogger.ProfileGCP))
+
+	resource := resourceLogs[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "product" {
+		t.Errorf("Wrong resource attributes %+v.", resource)
+	}
+
+	record := resourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if _, ok := otlpAttribute(record.Attributes, "logging.googleapis.com/labels"); ok {
+		t.Error("Attribute logging.googleapis.com/labels present.")
+	}
+}
+
+func TestWithOTLPExporter_FatalDoesNotRetry(t *testing.T) {
+	var attempts int32
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	exporter := otlp.New(collector.URL,
+		otlp.WithFlushInterval(time.Hour),
+		otlp.WithRetry(time.Second, time.Second, time.Minute),
+		otlp.WithErrorHandler(func(error) {}),
+	)
+
+	exited := make(chan int, 1)
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(io.Discard),
+		standardlogger.WithOTLPExporter(exporter),
+		standardlogger.WithExitFunc(func(code int) { exited <- code }),
+	)
+
+	go log.Fatal("Fatal msg", 1)
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Fatal waited for the unavailable endpoint.")
+	}
+
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Errorf("Expected 1 attempt, got %d.", n)
+	}
+}
+
+func TestWithOTLPExporter_FatalAbortsRetry(t *testing.T) {
+	var attempts, records int32
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+
+		body, _ := io.ReadAll(r.Body)
+		atomic.AddInt32(&records, int32(strings.Count(string(body), `"body"`)))
+
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	exporter := otlp.New(collector.URL,
+		otlp.WithFlushInterval(10*time.Millisecond),
+		otlp.WithRetry(time.Minute, time.Minute, time.Hour),
+		otlp.WithErrorHandler(func(error) {}),
+	)
+
+	exited := make(chan int, 1)
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(io.Discard),
+		standardlogger.WithOTLPExporter(exporter),
+		standardlogger.WithExitFunc(func(code int) { exited <- code }),
+	)
+
+	log.Info("Info msg")
+
+	// waits for the background export to fail and wait to be retried.
+	for start := time.Now(); atomic.LoadInt32(&attempts) == 0; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("Info wasn't exported.")
+		}
+	}
+
+	go log.Fatal("Fatal msg", 1)
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Fatal waited for the retry of the background export.")
+	}
+
+	// the info record is attempted once more, together with the fatal one.
+	if n, r := atomic.LoadInt32(&attempts), atomic.LoadInt32(&records); n != 2 || r != 3 {
+		t.Errorf("Expected 2 attempts with 3 records, got %d with %d.", n, r)
+	}
+}
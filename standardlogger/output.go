@@ -0,0 +1,141 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/standardlogger/filesink"
+)
+
+// WithOutput returns Option that writes entries below the error level to w instead of stdout.
+// If w implements zapcore.WriteSyncer, Flush syncs it.
+func WithOutput(w io.Writer) Option {
+	return func(ls *loggerSettings) {
+		ls.output = w
+		ls.singleOutput = false
+	}
+}
+
+// WithErrorOutput returns Option that writes error, panic and fatal entries to w instead of stderr.
+// If w implements zapcore.WriteSyncer, Flush syncs it.
+func WithErrorOutput(w io.Writer) Option {
+	return func(ls *loggerSettings) {
+		ls.errorOutput = w
+		ls.singleOutput = false
+	}
+}
+
+// WithSingleOutput returns Option that writes entries of all levels to w.
+func WithSingleOutput(w io.Writer) Option {
+	return func(ls *loggerSettings) {
+		ls.output = w
+		ls.errorOutput = w
+		ls.singleOutput = true
+	}
+}
+
+// WithFileOutput returns Option that writes entries of all levels to the rotating file sink.
+// The logger takes ownership of the sink and closes it on Close.
+func WithFileOutput(sink *filesink.Sink) Option {
+	return func(ls *loggerSettings) {
+		WithSingleOutput(sink)(ls)
+		ls.closers = append(ls.closers, sink)
+	}
+}
+
+// getOutputs returns the locked outputs for low and high priority entries.
+// Standard streams are resolved on every call, so they can be replaced before the logger is created.
+func getOutputs(settings loggerSettings) (zapcore.WriteSyncer, zapcore.WriteSyncer) {
+	var output io.Writer = os.Stdout
+	if settings.output != nil {
+		output = settings.output
+	}
+
+	var errorOutput io.Writer = os.Stderr
+
+	switch {
+	case settings.singleOutput:
+		errorOutput = output
+	case settings.errorOutput != nil:
+		errorOutput = settings.errorOutput
+	}
+
+	locked := lockOutputs(output, errorOutput)
+
+	return locked[0], locked[1]
+}
+
+// lockOutputs locks every distinct writer once, so entries written to the same writer through different outputs
+// don't interleave.
+func lockOutputs(writers ...io.Writer) []zapcore.WriteSyncer {
+	lockedWriters := make(map[io.Writer]zapcore.WriteSyncer, len(writers))
+	locked := make([]zapcore.WriteSyncer, len(writers))
+
+	for i, w := range writers {
+		// writers of types that can't be map keys, such as slices, are locked separately.
+		if !reflect.TypeOf(w).Comparable() {
+			locked[i] = zapcore.Lock(addSync(w))
+
+			continue
+		}
+
+		if _, ok := lockedWriters[w]; !ok {
+			lockedWriters[w] = zapcore.Lock(addSync(w))
+		}
+
+		locked[i] = lockedWriters[w]
+	}
+
+	return locked
+}
+
+// WithErrorHandler returns Option that sets the handler of errors of writing entries to the outputs,
+// including entries written in the background by WithAsync, and of syncing them on Flush.
+// By default, write errors are written to stderr and Flush ignores sync errors.
+func WithErrorHandler(handler func(error)) Option {
+	return func(ls *loggerSettings) {
+		ls.errorHandler = handler
+	}
+}
+
+// addSync converts w into a zapcore.WriteSyncer, ignoring errors of syncing standard streams that don't support it.
+func addSync(w io.Writer) zapcore.WriteSyncer {
+	if file, ok := w.(*os.File); ok && (file == os.Stdout || file == os.Stderr) {
+		return stdSyncer{WriteSyncer: file}
+	}
+
+	return zapcore.AddSync(w)
+}
+
+// stdSyncer ignores the EINVAL and ENOTTY errors returned when syncing a terminal or a pipe.
+type stdSyncer struct {
+	zapcore.WriteSyncer
+}
+
+func (s stdSyncer) Sync() error {
+	err := s.WriteSyncer.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+
+	return err //nolint:wrapcheck //returned as is to satisfy zapcore.WriteSyncer
+}
@@ -0,0 +1,308 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+	"github.com/dataphos/lib-logger/standardlogger/filesink"
+)
+
+func TestWithOutput(t *testing.T) {
+	var output, errorOutput bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{"key0": "val0"},
+		standardlogger.WithOutput(&output),
+		standardlogger.WithErrorOutput(&errorOutput),
+	)
+
+	log.Info("Info msg")
+	log.Warn("Warn msg")
+	log.Error("Error msg", 1000)
+
+	out := output.String()
+	for _, wantSubstring := range []string{"\"msg\":\"Info msg\"", "\"msg\":\"Warn msg\"", "\"key0\":\"val0\""} {
+		if !strings.Contains(out, wantSubstring) {
+			t.Errorf("Output missing, want substring '%s'.", wantSubstring)
+		}
+	}
+
+	if strings.Contains(out, "Error msg") {
+		t.Error("Error written to output.")
+	}
+
+	errOut := errorOutput.String()
+	for _, wantSubstring := range []string{"\"msg\":\"Error msg\"", "\"code\":1000"} {
+		if !strings.Contains(errOut, wantSubstring) {
+			t.Errorf("Error output missing, want substring '%s'.", wantSubstring)
+		}
+	}
+
+	if strings.Contains(errOut, "Info msg") {
+		t.Error("Info written to error output.")
+	}
+}
+
+func TestWithSingleOutput(t *testing.T) {
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{}, standardlogger.WithSingleOutput(&output))
+
+	log.Info("Info msg")
+	log.Error("Error msg", 0)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+
+	expectedNumberOfLines := 2
+	if len(lines) != expectedNumberOfLines {
+		t.Fatalf("Wrong number of lines %d, want %d.", len(lines), expectedNumberOfLines)
+	}
+
+	if !strings.Contains(lines[0], "Info msg") || !strings.Contains(lines[1], "Error msg") {
+		t.Errorf("Wrong output %s.", output.String())
+	}
+}
+
+func TestWithOutput_ResetsSingleOutput(t *testing.T) {
+	var single, output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{},
+		standardlogger.WithSingleOutput(&single),
+		standardlogger.WithOutput(&output),
+	)
+
+	log.Info("Info msg")
+	log.Error("Error msg", 0)
+
+	if !strings.Contains(output.String(), "Info msg") || strings.Contains(output.String(), "Error msg") {
+		t.Errorf("Wrong output %s.", output.String())
+	}
+
+	if !strings.Contains(single.String(), "Error msg") || strings.Contains(single.String(), "Info msg") {
+		t.Errorf("Wrong error output %s.", single.String())
+	}
+}
+
+func TestWithFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	sink, err := filesink.New(path, filesink.WithMaxSize(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	log := standardlogger.New(logger.Labels{"key0": "val0"}, standardlogger.WithFileOutput(sink))
+	log.Info("Info msg")
+	log.Error("Error msg", 1000)
+	log.Flush()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, wantSubstring := range []string{"\"msg\":\"Info msg\"", "\"msg\":\"Error msg\"", "\"key0\":\"val0\""} {
+		if !strings.Contains(string(content), wantSubstring) {
+			t.Errorf("File missing, want substring '%s'.", wantSubstring)
+		}
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct {
+	syncErr error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
+
+func (w failingWriter) Sync() error {
+	return w.syncErr
+}
+
+// errorRecorder is an error handler that keeps the errors it received.
+type errorRecorder struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (r *errorRecorder) handle(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, err)
+}
+
+func (r *errorRecorder) all() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]error(nil), r.errs...)
+}
+
+func TestStandardLog_Sync(t *testing.T) {
+	log := standardlogger.New(nil, standardlogger.WithSingleOutput(&closeAwareWriter{syncErr: errSync}))
+
+	if err := log.Sync(); !errors.Is(err, errSync) {
+		t.Errorf("Expected sync error, got %v.", err)
+	}
+}
+
+func TestStandardLog_SyncStdout(t *testing.T) {
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer read.Close()
+	defer write.Close()
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = write, write
+
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+	}()
+
+	log := standardlogger.New(nil)
+
+	// syncing a pipe fails with EINVAL on Linux, which is ignored.
+	if err := log.Sync(); err != nil {
+		t.Errorf("Sync failed: %v.", err)
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	recorder := &errorRecorder{}
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(failingWriter{syncErr: errSync}),
+		standardlogger.WithErrorHandler(recorder.handle),
+	)
+
+	log.Info("Info msg")
+	log.Flush()
+
+	errs := recorder.all()
+	if len(errs) != 2 || !errors.Is(errs[0], errWrite) || !errors.Is(errs[1], errSync) {
+		t.Errorf("Expected write and sync errors, got %v.", errs)
+	}
+}
+
+func TestWithErrorHandler_Async(t *testing.T) {
+	recorder := &errorRecorder{}
+
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(failingWriter{}),
+		standardlogger.WithAsync(10, time.Millisecond, standardlogger.OverflowBlock),
+		standardlogger.WithErrorHandler(recorder.handle),
+	)
+	defer log.Close()
+
+	log.Info("Info msg")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(recorder.all()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if errs := recorder.all(); len(errs) != 1 || !errors.Is(errs[0], errWrite) {
+		t.Errorf("Expected write error, got %v.", errs)
+	}
+}
+
+func TestStandardLog_SyncAsyncWriteError(t *testing.T) {
+	log := standardlogger.New(nil,
+		standardlogger.WithSingleOutput(failingWriter{}),
+		standardlogger.WithAsync(10, time.Hour, standardlogger.OverflowBlock),
+	)
+	defer log.Close()
+
+	log.Info("Info msg")
+
+	if err := log.Sync(); !errors.Is(err, errWrite) {
+		t.Errorf("Expected write error, got %v.", err)
+	}
+}
+
+// overlapWriter counts writes that start while another write is in progress.
+type overlapWriter struct {
+	mu       sync.Mutex
+	active   int
+	overlaps int
+	lines    int
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.active++
+
+	if w.active > 1 {
+		w.overlaps++
+	}
+	w.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	w.mu.Lock()
+	w.active--
+	w.lines++
+	w.mu.Unlock()
+
+	return len(p), nil
+}
+
+func TestWithOutput_SharedWithErrorOutput(t *testing.T) {
+	w := &overlapWriter{}
+
+	log := standardlogger.New(logger.Labels{},
+		standardlogger.WithOutput(w),
+		standardlogger.WithErrorOutput(w),
+	)
+
+	var wg sync.WaitGroup
+
+	entries := 20
+	for i := 0; i < entries; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			log.Info("Info msg")
+		}()
+
+		go func() {
+			defer wg.Done()
+			log.Error("Error msg", 0)
+		}()
+	}
+
+	wg.Wait()
+
+	if w.overlaps != 0 || w.lines != 2*entries {
+		t.Errorf("Got %d overlapping writes of %d, want none of %d.", w.overlaps, w.lines, 2*entries)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+// Profile selects the schema of JSON entries, i.e. the keys and shapes expected by a log backend.
+// Profiles only apply to FormatJSON, the other formats always use the default schema.
+// With FormatAuto, setting a profile other than ProfileDefault selects FormatJSON.
+type Profile int
+
+const (
+	// ProfileDefault emits zap's keys (ts, level, msg, caller, stacktrace), labels at top level and the error code as code.
+	ProfileDefault Profile = iota + 1
+	// ProfileECS emits Elastic Common Schema keys.
+	ProfileECS
+	// ProfileGCP emits Google Cloud Logging structured logging keys, including severity and Error Reporting types.
+	ProfileGCP
+	// ProfileAzure emits Application Insights keys, including the numeric severityLevel and exceptions.
+	ProfileAzure
+)
+
+// WithProfile returns Option that sets the schema of JSON entries.
+func WithProfile(profile Profile) Option {
+	return func(ls *loggerSettings) {
+		ls.profile = profile
+	}
+}
+
+// schema describes how a Profile shapes entries.
+type schema struct {
+	// codeField turns the error code of Error, Fatal and Panic into a field.
+	codeField func(code uint64) zap.Field
+	// labelFields turns labels into fields, including the tags.
+	labelFields func(labels logger.Labels) []zap.Field
+	// encoderConfig adjusts the keys and encoders of the JSON encoder.
+	encoderConfig func(conf *zapcore.EncoderConfig)
+	// encodeEntry adjusts the entry and its fields right before encoding,
+	// e.g. to add fields derived from the caller. Optional.
+	encodeEntry func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field)
+	// reservedKeys are the top-level keys the schema writes, which labels and fields may not use.
+	reservedKeys []string
+}
+
+var defaultSchema = &schema{
+	codeField: func(code uint64) zap.Field {
+		return zap.Uint64("code", code)
+	},
+	labelFields: func(labels logger.Labels) []zap.Field {
+		return append(GetLabelsAsZapFields(labels), zap.Strings(tagsKey, GetLabelsKeys(labels)))
+	},
+	encoderConfig: func(*zapcore.EncoderConfig) {},
+	reservedKeys:  []string{"ts", "level", "msg", "caller", "stacktrace", "code", tagsKey},
+}
+
+func getSchema(settings loggerSettings) *schema {
+	if resolveFormat(settings) != FormatJSON {
+		return defaultSchema
+	}
+
+	switch settings.profile {
+	case ProfileECS:
+		return ecsSchema
+	case ProfileGCP:
+		return gcpSchema
+	case ProfileAzure:
+		return azureSchema
+	default:
+		return defaultSchema
+	}
+}
+
+// schemaEncoder adjusts every entry with the schema's encodeEntry before delegating to the wrapped encoder.
+type schemaEncoder struct {
+	zapcore.Encoder
+	schema *schema
+}
+
+func (e *schemaEncoder) Clone() zapcore.Encoder {
+	return &schemaEncoder{
+		Encoder: e.Encoder.Clone(),
+		schema:  e.schema,
+	}
+}
+
+func (e *schemaEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent, fields = e.schema.encodeEntry(ent, fields)
+
+	return e.Encoder.EncodeEntry(ent, fields) //nolint:wrapcheck //encoder errors are handled by zap
+}
+
+// prependFields returns a new slice with entryFields followed by fields.
+func prependFields(entryFields, fields []zapcore.Field) []zapcore.Field {
+	all := make([]zapcore.Field, 0, len(entryFields)+len(fields))
+	all = append(all, entryFields...)
+
+	return append(all, fields...)
+}
+
+// labelsObject marshals labels as a nested object, sorted by key.
+type labelsObject logger.Labels
+
+func (l labelsObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range logger.Labels(l).Keys() {
+		enc.AddString(key, l[key])
+	}
+
+	return nil
+}
+
+// callerFile returns the package/file part of the caller, without the line.
+func callerFile(caller zapcore.EntryCaller) string {
+	return strings.TrimSuffix(caller.TrimmedPath(), ":"+strconv.Itoa(caller.Line))
+}
@@ -0,0 +1,354 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const (
+	redactedMask   = "[REDACTED]"
+	redactedPrefix = "sha256:"
+	redactedLength = 16
+
+	// keySeparator stands in for "/" when matching key patterns, so path.Match does not treat it as a separator.
+	keySeparator = "\x00"
+)
+
+// RedactionStrategy defines what happens to a redacted value.
+type RedactionStrategy int
+
+const (
+	// RedactMask replaces the value with [REDACTED].
+	RedactMask RedactionStrategy = iota + 1
+	// RedactHash replaces the value with sha256: followed by the first 16 hex digits of its SHA-256 hash,
+	// so equal values can still be correlated. Low-entropy values, such as emails, can be recovered by brute force.
+	RedactHash
+	// RedactDrop removes the field or label. Matches in messages and inside string values are removed.
+	RedactDrop
+)
+
+// Patterns of common personal and secret values, to be used as RedactionRule.ValuePatterns.
+// Matches of CreditCardPattern are only redacted if they pass the Luhn check, so IDs and timestamps are kept.
+var (
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	JWTPattern        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	EmailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactionRule selects values to redact and how.
+// Keys and KeyPatterns match keys of labels and fields, including keys of nested maps, case-insensitively;
+// the whole value of a matched key is redacted. KeyPatterns are globs as in path.Match, e.g. *token*,
+// except that * and ? also match "/".
+// ValuePatterns match parts of string values and messages; only the matched parts are redacted.
+type RedactionRule struct {
+	Keys          []string
+	KeyPatterns   []string
+	ValuePatterns []*regexp.Regexp
+	Strategy      RedactionStrategy
+}
+
+// WithRedaction returns Option that redacts labels, fields and messages before they are encoded.
+// Rules are applied in order and the first rule matching a key decides its strategy.
+// Values of types other than strings, maps, slices and errors are converted into their JSON representation first.
+func WithRedaction(rules ...RedactionRule) Option {
+	return func(ls *loggerSettings) {
+		ls.redaction = append(ls.redaction, rules...)
+	}
+}
+
+// redactor applies redaction rules. A nil redactor returns everything unchanged.
+type redactor struct {
+	rules []RedactionRule
+}
+
+func newRedactor(rules []RedactionRule) *redactor {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	normalized := make([]RedactionRule, len(rules))
+
+	for i, rule := range rules {
+		normalized[i] = rule
+		normalized[i].Keys = lowerAll(rule.Keys)
+		normalized[i].KeyPatterns = lowerAll(rule.KeyPatterns)
+	}
+
+	return &redactor{rules: normalized}
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+
+	return lowered
+}
+
+func (r *redactor) message(msg string) string {
+	if r == nil {
+		return msg
+	}
+
+	return r.string(msg)
+}
+
+func (r *redactor) labels(labels logger.Labels) logger.Labels {
+	if r == nil {
+		return labels
+	}
+
+	redacted := make(logger.Labels, len(labels))
+
+	for key, val := range labels {
+		if rule, ok := r.keyRule(key); ok {
+			if rule.Strategy != RedactDrop {
+				redacted[key] = redactWhole(rule.Strategy, val)
+			}
+
+			continue
+		}
+
+		redacted[key] = r.string(val)
+	}
+
+	return redacted
+}
+
+func (r *redactor) fields(fields logger.Fields) logger.Fields {
+	if r == nil || len(fields) == 0 {
+		return fields
+	}
+
+	return r.object(fields)
+}
+
+func (r *redactor) typedFields(fields []logger.Field) []logger.Field {
+	if r == nil || len(fields) == 0 {
+		return fields
+	}
+
+	redacted := make([]logger.Field, 0, len(fields))
+
+	for _, field := range fields {
+		if rule, ok := r.keyRule(field.Key); ok && field.Key != "" {
+			if rule.Strategy != RedactDrop {
+				redacted = append(redacted, logger.String(field.Key, redactWhole(rule.Strategy, typedFieldValue(field))))
+			}
+
+			continue
+		}
+
+		redacted = append(redacted, r.typedField(field))
+	}
+
+	return redacted
+}
+
+// typedField redacts the values of a field; error messages are redacted when the error is encoded.
+func (r *redactor) typedField(field logger.Field) logger.Field {
+	switch field.Type {
+	case logger.FieldTypeString:
+		field.String = r.string(field.String)
+	case logger.FieldTypeObject, logger.FieldTypeArray:
+		if nested, ok := field.Interface.([]logger.Field); ok {
+			field.Interface = r.typedFields(nested)
+		}
+	case logger.FieldTypeAny:
+		field.Interface = r.value(field.Interface)
+	case logger.FieldTypeInt, logger.FieldTypeUint, logger.FieldTypeFloat, logger.FieldTypeBool,
+		logger.FieldTypeDuration, logger.FieldTypeTime, logger.FieldTypeError:
+	}
+
+	return field
+}
+
+// typedFieldValue returns the value of a field to be hashed.
+func typedFieldValue(field logger.Field) interface{} {
+	switch field.Type {
+	case logger.FieldTypeString:
+		return field.String
+	case logger.FieldTypeInt, logger.FieldTypeUint, logger.FieldTypeBool, logger.FieldTypeDuration:
+		return field.Integer
+	case logger.FieldTypeFloat:
+		return math.Float64frombits(uint64(field.Integer))
+	default:
+		return fmt.Sprint(field.Interface)
+	}
+}
+
+func (r *redactor) object(fields map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+
+	for key, val := range fields {
+		if rule, ok := r.keyRule(key); ok {
+			if rule.Strategy != RedactDrop {
+				redacted[key] = redactWhole(rule.Strategy, val)
+			}
+
+			continue
+		}
+
+		redacted[key] = r.value(val)
+	}
+
+	return redacted
+}
+
+func (r *redactor) value(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return r.string(v)
+	case logger.Fields:
+		return logger.Fields(r.object(v))
+	case map[string]interface{}:
+		return r.object(v)
+	case logger.Labels:
+		return r.labels(v)
+	case map[string]string:
+		return map[string]string(r.labels(v))
+	case []string:
+		redacted := make([]string, len(v))
+		for i, elem := range v {
+			redacted[i] = r.string(elem)
+		}
+
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, elem := range v {
+			redacted[i] = r.value(elem)
+		}
+
+		return redacted
+	case error:
+		// errors keep their type unless their message needs redacting.
+		if msg := v.Error(); r.string(msg) != msg {
+			return r.string(msg)
+		}
+
+		return v
+	case nil, bool, time.Time, time.Duration,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128:
+		return v
+	default:
+		// structs and other types are redacted through their JSON representation.
+		return r.value(normalizeValue(v))
+	}
+}
+
+func (r *redactor) string(val string) string {
+	for _, rule := range r.rules {
+		for _, pattern := range rule.ValuePatterns {
+			pattern := pattern
+
+			val = pattern.ReplaceAllStringFunc(val, func(match string) string {
+				if pattern == CreditCardPattern && !luhnValid(match) {
+					return match
+				}
+
+				if rule.Strategy == RedactDrop {
+					return ""
+				}
+
+				return redactWhole(rule.Strategy, match)
+			})
+		}
+	}
+
+	return val
+}
+
+// luhnValid reports whether the digits of number, ignoring other characters, pass the Luhn check of card numbers.
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			continue
+		}
+
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 { //nolint:gomnd //digits of the doubled value are summed
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// keyRule returns the first rule matching key.
+func (r *redactor) keyRule(key string) (RedactionRule, bool) {
+	key = strings.ToLower(key)
+
+	for _, rule := range r.rules {
+		for _, k := range rule.Keys {
+			if k == key {
+				return rule, true
+			}
+		}
+
+		for _, pattern := range rule.KeyPatterns {
+			if matchKeyPattern(pattern, key) {
+				return rule, true
+			}
+		}
+	}
+
+	return RedactionRule{}, false
+}
+
+// matchKeyPattern reports whether key matches the glob pattern. Unlike in path.Match,
+// "/" is an ordinary character, so *token* also matches keys such as auth/token.
+func matchKeyPattern(pattern, key string) bool {
+	matched, err := path.Match(strings.ReplaceAll(pattern, "/", keySeparator), strings.ReplaceAll(key, "/", keySeparator))
+
+	return err == nil && matched
+}
+
+// redactWhole returns the replacement of val for the mask and hash strategies.
+func redactWhole(strategy RedactionStrategy, val interface{}) string {
+	if strategy != RedactHash {
+		return redactedMask
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		s = fmt.Sprint(val)
+	}
+
+	sum := sha256.Sum256([]byte(s))
+
+	return redactedPrefix + hex.EncodeToString(sum[:])[:redactedLength]
+}
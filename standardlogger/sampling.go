@@ -0,0 +1,132 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const (
+	defaultSamplingTick       = time.Second
+	defaultSamplingFirst      = 100
+	defaultSamplingThereafter = 100
+)
+
+// SamplingOverride replaces the sampling rates for a single level.
+type SamplingOverride struct {
+	Level      logger.Level
+	First      int
+	Thereafter int
+}
+
+type samplingSettings struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+	overrides  []SamplingOverride
+}
+
+// WithSampling returns Option that samples repeated log entries.
+// Within every tick, the first entries with the same level and message are logged as-is,
+// after that only every thereafter-th entry is logged and the rest are dropped.
+// Overrides set different rates for individual levels.
+func WithSampling(tick time.Duration, first, thereafter int, overrides ...SamplingOverride) Option {
+	return func(ls *loggerSettings) {
+		ls.sampling = &samplingSettings{
+			tick:       tick,
+			first:      first,
+			thereafter: thereafter,
+			overrides:  overrides,
+		}
+	}
+}
+
+// WithoutSampling returns Option that disables sampling, so no log entries are dropped.
+func WithoutSampling() Option {
+	return func(ls *loggerSettings) {
+		ls.sampling = nil
+	}
+}
+
+// samplingCounter counts the entries dropped by sampling.
+type samplingCounter struct {
+	dropped uint64
+}
+
+func (c *samplingCounter) hook(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		atomic.AddUint64(&c.dropped, 1)
+	}
+}
+
+func (c *samplingCounter) load() uint64 {
+	return atomic.LoadUint64(&c.dropped)
+}
+
+// samplingCore routes every entry to the sampler configured for its level.
+type samplingCore struct {
+	zapcore.Core
+	byLevel map[zapcore.Level]zapcore.Core
+}
+
+func newSamplingCore(core zapcore.Core, settings *samplingSettings, counter *samplingCounter) zapcore.Core {
+	hook := zapcore.SamplerHook(counter.hook)
+
+	byLevel := make(map[zapcore.Level]zapcore.Core, len(settings.overrides))
+	for _, override := range settings.overrides {
+		byLevel[getLevelAsZapLevel(override.Level)] = zapcore.NewSamplerWithOptions(
+			core, settings.tick, override.First, override.Thereafter, hook,
+		)
+	}
+
+	return &samplingCore{
+		Core:    zapcore.NewSamplerWithOptions(core, settings.tick, settings.first, settings.thereafter, hook),
+		byLevel: byLevel,
+	}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	byLevel := make(map[zapcore.Level]zapcore.Core, len(c.byLevel))
+	for lvl, core := range c.byLevel {
+		byLevel[lvl] = core.With(fields)
+	}
+
+	return &samplingCore{
+		Core:    c.Core.With(fields),
+		byLevel: byLevel,
+	}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core, ok := c.byLevel[ent.Level]; ok {
+		return core.Check(ent, ce)
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// DroppedBySampling returns the number of log entries dropped by sampling since the root logger was created.
+func (l *StandardLog) DroppedBySampling() uint64 {
+	if l.sampling == nil {
+		return 0
+	}
+
+	return l.sampling.load()
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestSampling_EnabledByDefault(t *testing.T) {
+	var output bytes.Buffer
+
+	log := standardlogger.New(logger.Labels{}, standardlogger.WithSingleOutput(&output))
+
+	for i := 0; i < 150; i++ {
+		log.Info("flood")
+	}
+
+	expectedNumberOfLogs := 100
+	if n := strings.Count(output.String(), "\n"); n != expectedNumberOfLogs {
+		t.Errorf("Wrong number of logs %d, want %d.", n, expectedNumberOfLogs)
+	}
+
+	expectedDropped := uint64(50)
+	if dropped := log.(*standardlogger.StandardLog).DroppedBySampling(); dropped != expectedDropped { //nolint:forcetypeassert //not necessary in tests.
+		t.Errorf("Wrong number of dropped logs %d, want %d.", dropped, expectedDropped)
+	}
+}
+
+func TestWithSampling(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithSampling(time.Minute, 2, 3))
+
+	for i := 0; i < 10; i++ {
+		log.Info("flood")
+	}
+
+	// entries 1 and 2 pass as the first ones, entries 5 and 8 as every third one thereafter.
+	expectedNumberOfLogs := 4
+	if logs.Len() != expectedNumberOfLogs {
+		t.Errorf("Wrong number of logs %d, want %d.", logs.Len(), expectedNumberOfLogs)
+	}
+
+	expectedDropped := uint64(6)
+	if dropped := log.(*standardlogger.StandardLog).DroppedBySampling(); dropped != expectedDropped { //nolint:forcetypeassert //not necessary in tests.
+		t.Errorf("Wrong number of dropped logs %d, want %d.", dropped, expectedDropped)
+	}
+}
+
+func TestWithSampling_DistinctMessages(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithSampling(time.Minute, 1, 0))
+
+	log.Info("first")
+	log.Info("second")
+	log.Warn("first")
+
+	expectedNumberOfLogs := 3
+	if logs.Len() != expectedNumberOfLogs {
+		t.Errorf("Wrong number of logs %d, want %d.", logs.Len(), expectedNumberOfLogs)
+	}
+}
+
+func TestWithSampling_LevelOverride(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(
+		logger.Labels{},
+		standardlogger.WithSampling(time.Minute, 1, 0, standardlogger.SamplingOverride{
+			Level: logger.LevelError,
+			First: 5,
+		}),
+	)
+
+	for i := 0; i < 10; i++ {
+		log.Info("flood")
+		log.Error("flood", 0)
+	}
+
+	if n := standardlogger.CountByLevel(logs, logger.LevelInfo); n != 1 {
+		t.Errorf("Wrong number of info logs %d, want %d.", n, 1)
+	}
+
+	if n := standardlogger.CountByLevel(logs, logger.LevelError); n != 5 {
+		t.Errorf("Wrong number of error logs %d, want %d.", n, 5)
+	}
+}
+
+func TestWithoutSampling(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithoutSampling())
+
+	for i := 0; i < 150; i++ {
+		log.Info("flood")
+	}
+
+	expectedNumberOfLogs := 150
+	if logs.Len() != expectedNumberOfLogs {
+		t.Errorf("Wrong number of logs %d, want %d.", logs.Len(), expectedNumberOfLogs)
+	}
+
+	if dropped := log.(*standardlogger.StandardLog).DroppedBySampling(); dropped != 0 { //nolint:forcetypeassert //not necessary in tests.
+		t.Errorf("Wrong number of dropped logs %d, want %d.", dropped, 0)
+	}
+}
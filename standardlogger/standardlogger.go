@@ -17,21 +17,40 @@ package standardlogger
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger/otlp"
 )
 
 type StandardLog struct {
 	ZapLogger zapLogger
+	// base is the logger without labels, tags and fields, shared by all children of the root logger.
+	// For a StandardLog created without New, it is the ZapLogger it was created with, set by its first child.
+	base       zapLogger
+	labels     logger.Labels
+	fields     []zap.Field
+	level      *zap.AtomicLevel
+	schema     *schema
+	sampling   *samplingCounter
+	extractors []ContextExtractor
+	redactor   *redactor
+	lifecycle  *lifecycle
+	shutdown   *shutdown
+	errorCodes *errorCodes
+	// labelPolicy validates labels and renames reserved field keys, nil if no policy is set.
+	labelPolicy *logger.LabelPolicy
 }
 
 type zapLogger interface {
 	Sync() error
 	With(fields ...zap.Field) *zap.Logger
+	Log(lvl zapcore.Level, msg string, fields ...zap.Field)
+	Debug(msg string, fields ...zap.Field)
 	Info(msg string, fields ...zap.Field)
 	Warn(msg string, fields ...zap.Field)
 	Error(msg string, fields ...zap.Field)
@@ -41,19 +60,48 @@ type zapLogger interface {
 }
 
 type PanicContainer struct {
-	msg    string
-	Code   uint64
-	fields logger.Fields
+	msg         string
+	Code        uint64
+	fields      logger.Fields
+	typedFields []logger.Field
 }
 
 type Option func(*loggerSettings)
 
 type loggerSettings struct {
-	logLevel logger.Level
+	logLevel       logger.Level
+	sampling       *samplingSettings
+	extractors     []ContextExtractor
+	output         io.Writer
+	errorOutput    io.Writer
+	singleOutput   bool
+	format         Format
+	profile        Profile
+	otlpExporter   *otlp.Exporter
+	redaction      []RedactionRule
+	async          *asyncSettings
+	closers        []io.Closer
+	fallbackOutput io.Writer
+	errorHandler   func(error)
+
+	exitFunc        func(code int)
+	exitCode        func(code uint64) int
+	shutdownHooks   []ShutdownHook
+	shutdownTimeout time.Duration
+
+	errorCodeRegistry *logger.ErrorCodeRegistry
+	labelPolicy       *logger.LabelPolicy
 }
 
 var defaultSettings = loggerSettings{
 	logLevel: logger.LevelInfo,
+	format:   FormatAuto,
+	profile:  ProfileDefault,
+	sampling: &samplingSettings{
+		tick:       defaultSamplingTick,
+		first:      defaultSamplingFirst,
+		thereafter: defaultSamplingThereafter,
+	},
 }
 
 // WithLogLevel returns Option that sets the desired log level.
@@ -64,81 +112,169 @@ func WithLogLevel(logLevel logger.Level) Option {
 }
 
 func New(labels logger.Labels, opts ...Option) logger.Log {
-	settings := defaultSettings
-
-	for _, opt := range opts {
-		opt(&settings)
-	}
+	settings := newSettings(opts...)
 
-	zapLogLevel := getLevelAsZapLevel(settings.logLevel)
+	level := zap.NewAtomicLevelAt(getLevelAsZapLevel(settings.logLevel))
 
 	// Enabled returns true if the given level is at or above this level.
 	highPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= zapcore.ErrorLevel && zapLogLevel.Enabled(lvl)
+		return lvl >= zapcore.ErrorLevel && level.Enabled(lvl)
 	})
 
 	lowPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl < zapcore.ErrorLevel && zapLogLevel.Enabled(lvl)
+		return lvl < zapcore.ErrorLevel && level.Enabled(lvl)
 	})
 
-	consoleDebugging := zapcore.Lock(os.Stdout)
-	consoleErrors := zapcore.Lock(os.Stderr)
+	consoleDebugging, consoleErrors := getOutputs(settings)
 
-	// Set timestamp to be in RFC3339Nano format.
-	// This format is easily human-readable, unlike unix timestamp.
-	// Fluent Bit can parse this format without custom scripting.
-	conf := zap.NewProductionEncoderConfig()
-	conf.EncodeTime = zapcore.RFC3339NanoTimeEncoder
-	consoleEncoder := zapcore.NewJSONEncoder(conf)
+	var async *asyncOutputs
+	if settings.async != nil {
+		consoleDebugging, consoleErrors, async = newAsyncOutputs(settings.async, settings.errorHandler, consoleDebugging, consoleErrors)
+	}
 
-	core := zapcore.NewTee(
-		zapcore.NewCore(consoleEncoder, consoleErrors, highPriority),
-		zapcore.NewCore(consoleEncoder, consoleDebugging, lowPriority),
-	)
+	encoder := newEncoder(settings)
+
+	cores := []zapcore.Core{
+		zapcore.NewCore(encoder, consoleErrors, highPriority),
+		zapcore.NewCore(encoder, consoleDebugging, lowPriority),
+	}
+
+	if settings.otlpExporter != nil {
+		cores = append(cores, newOTLPCore(settings.otlpExporter, level))
+	}
+
+	log := newStandardLog(cores, labels, level, settings)
+	log.lifecycle.async = async
+
+	return log
+}
+
+func newSettings(opts ...Option) loggerSettings {
+	settings := defaultSettings
+
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	return settings
+}
+
+// newStandardLog combines the given cores and wraps them with sampling, labels, tags, caller and stacktrace information.
+func newStandardLog(cores []zapcore.Core, labels logger.Labels, level zap.AtomicLevel, settings loggerSettings) *StandardLog {
+	lifecycle := &lifecycle{
+		cores:        cores,
+		closers:      settings.closers,
+		errorHandler: settings.errorHandler,
+	}
+
+	var core zapcore.Core = &lifecycleCore{
+		cores:     cores,
+		fallback:  newFallbackCore(settings, level),
+		lifecycle: lifecycle,
+	}
+
+	var sampling *samplingCounter
+
+	if settings.sampling != nil {
+		sampling = &samplingCounter{}
+		core = newSamplingCore(core, settings.sampling, sampling)
+	}
 
 	// From a zapcore.Core, it's easy to construct a Logger.
-	zapLogger := zap.New(core, zap.AddCallerSkip(1),
-		zap.Fields(GetLabelsAsZapFields(labels)...),
-		zap.Fields(zap.Strings("tags", GetLabelsKeys(labels))),
+	base := zap.New(core, zap.AddCallerSkip(1),
 		zap.AddCaller(),
 		zap.AddStacktrace(zap.ErrorLevel),
+		zap.WithFatalHook(noopFatalHook{}),
 	)
 
-	return &StandardLog{
-		ZapLogger: zapLogger,
+	redactor := newRedactor(settings.redaction)
+	schema := getSchema(settings)
+
+	log := &StandardLog{
+		ZapLogger:   base,
+		base:        base,
+		level:       &level,
+		schema:      schema,
+		sampling:    sampling,
+		extractors:  settings.extractors,
+		redactor:    redactor,
+		lifecycle:   lifecycle,
+		shutdown:    newShutdown(settings),
+		errorCodes:  newErrorCodes(settings.errorCodeRegistry),
+		labelPolicy: newLabelPolicy(settings.labelPolicy, schema),
 	}
+
+	log.labels = log.validLabels(redactor.labels(labels.Clone()))
+	log.ZapLogger = base.With(getLabelsContext(schema, log.labels, nil)...)
+	log.warnDuplicateErrorCodes()
+
+	return log
+}
+
+// getLabelsContext returns labels, tags and fields in the order they are attached to every entry.
+func getLabelsContext(schema *schema, labels logger.Labels, fields []zap.Field) []zap.Field {
+	labelFields := schema.labelFields(labels)
+
+	context := make([]zap.Field, 0, len(labelFields)+len(fields))
+	context = append(context, labelFields...)
+
+	return append(context, fields...)
+}
+
+func (l *StandardLog) Tracew(msg string, fields logger.Fields) {
+	l.ZapLogger.Log(traceLevel, l.redactor.message(msg), l.zapFields(fields, nil)...)
+}
+
+func (l *StandardLog) Trace(msg string) {
+	l.ZapLogger.Log(traceLevel, l.redactor.message(msg))
+}
+
+func (l *StandardLog) Debugw(msg string, fields logger.Fields) {
+	l.ZapLogger.Debug(l.redactor.message(msg), l.zapFields(fields, nil)...)
+}
+
+func (l *StandardLog) Debug(msg string) {
+	l.ZapLogger.Debug(l.redactor.message(msg))
 }
 
 func (l *StandardLog) Infow(msg string, fields logger.Fields) {
-	l.ZapLogger.Info(msg, GetLoggerFieldsAsZapFields(fields)...)
+	l.ZapLogger.Info(l.redactor.message(msg), l.zapFields(fields, nil)...)
 }
 
 func (l *StandardLog) Info(msg string) {
-	l.ZapLogger.Info(msg)
+	l.ZapLogger.Info(l.redactor.message(msg))
 }
 
 func (l *StandardLog) Warnw(msg string, fields logger.Fields) {
-	l.ZapLogger.Warn(msg, GetLoggerFieldsAsZapFields(fields)...)
+	l.ZapLogger.Warn(l.redactor.message(msg), l.zapFields(fields, nil)...)
 }
 
 func (l *StandardLog) Warn(msg string) {
-	l.ZapLogger.Warn(msg)
+	l.ZapLogger.Warn(l.redactor.message(msg))
 }
 
 func (l *StandardLog) Errorw(msg string, code uint64, fields logger.Fields) {
-	l.ZapLogger.With(zap.Uint64("code", code)).Error(msg, GetLoggerFieldsAsZapFields(fields)...)
+	l.checkErrorCode(code)
+	l.ZapLogger.Log(l.errorLevel(code), l.redactor.message(msg), l.getFieldsWithCode(code, fields)...)
 }
 
 func (l *StandardLog) Error(msg string, code uint64) {
-	l.ZapLogger.With(zap.Uint64("code", code)).Error(msg)
+	l.checkErrorCode(code)
+	l.ZapLogger.Log(l.errorLevel(code), l.redactor.message(msg), l.codeFields(code)...)
 }
 
+// Fatalw logs the entry, runs the shutdown hooks, closes the log and exits with the exit code derived from code.
 func (l *StandardLog) Fatalw(msg string, code uint64, fields logger.Fields) {
-	l.ZapLogger.With(zap.Uint64("code", code)).Fatal(msg, GetLoggerFieldsAsZapFields(fields)...)
+	l.checkErrorCode(code)
+	l.ZapLogger.Fatal(l.redactor.message(msg), l.getFieldsWithCode(code, fields)...)
+	l.exit(code)
 }
 
+// Fatal logs the entry, runs the shutdown hooks, closes the log and exits with the exit code derived from code.
 func (l *StandardLog) Fatal(msg string, code uint64) {
-	l.ZapLogger.With(zap.Uint64("code", code)).Fatal(msg)
+	l.checkErrorCode(code)
+	l.ZapLogger.Fatal(l.redactor.message(msg), l.codeFields(code)...)
+	l.exit(code)
 }
 
 func (l *StandardLog) Panicw(msg string, code uint64, fields logger.Fields) {
@@ -154,21 +290,116 @@ func (l *StandardLog) PanicLogger() {
 	if r := recover(); r != nil { //nolint:varnamelen //short variable makes sense here
 		panicData, ok := r.(*PanicContainer)
 		if ok {
-			fields := panicData.fields
-			l.ZapLogger.With(zap.Uint64("code", panicData.Code)).Panic(panicData.msg, GetLoggerFieldsAsZapFields(fields)...)
+			l.checkErrorCode(panicData.Code)
+
+			zapFields := l.getFieldsWithCode(panicData.Code, panicData.fields)
+			zapFields = append(zapFields, l.typedZapFields(panicData.typedFields, fieldKeys(zapFields))...)
+
+			log, fields := l.errorLogger(zapFields)
+			log.Panic(l.redactor.message(panicData.msg), fields...)
 		} else {
 			l.ZapLogger.
-				Panic(fmt.Sprint(r))
+				Panic(l.redactor.message(fmt.Sprint(r)))
 		}
 	}
 }
 
-func (l *StandardLog) Close() {
-	l.Flush()
+// With returns a child logger that adds fields to every entry.
+// The child shares outputs with its parent.
+func (l *StandardLog) With(fields logger.Fields) logger.Log {
+	// fields of the child are part of every entry, so they may not use the keys written only in some entries.
+	zapFields := l.zapFields(fields, l.entryKeys())
+
+	child := l.clone()
+	child.base = l.getBase()
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], zapFields...)
+	child.ZapLogger = l.ZapLogger.With(zapFields...)
+
+	return child
+}
+
+// WithLabels returns a child logger with labels added to the parent's labels.
+// The child shares outputs with its parent and its tags contain keys of both parent's and added labels.
+func (l *StandardLog) WithLabels(labels logger.Labels) logger.Log {
+	child := l.clone()
+	child.base = l.getBase()
+	child.labels = l.labels.Clone().Add(l.validLabels(l.redactor.labels(labels)))
+	child.ZapLogger = child.base.With(getLabelsContext(l.getSchema(), child.labels, child.fields)...)
+
+	return child
+}
+
+// getBase returns the base logger, or ZapLogger if the log was created without New.
+func (l *StandardLog) getBase() zapLogger {
+	if l.base == nil {
+		return l.ZapLogger
+	}
+
+	return l.base
+}
+
+// getFieldsWithCode returns the error code fields followed by fields.
+// The code is passed with the entry, rather than attached with With, so the schema can reshape it when encoding.
+func (l *StandardLog) getFieldsWithCode(code uint64, fields logger.Fields) []zap.Field {
+	codeFields := l.codeFields(code)
+
+	return append(codeFields, l.zapFields(fields, fieldKeys(codeFields))...)
+}
+
+// zapFields redacts fields and converts them to zap fields. entryKeys are the keys the logger adds to the entry.
+func (l *StandardLog) zapFields(fields logger.Fields, entryKeys []string) []zap.Field {
+	return GetLoggerFieldsAsZapFields(l.policyFields(l.redactor.fields(fields), entryKeys))
 }
 
+// withOptions returns the zap logger with opts applied. Loggers other than *zap.Logger, e.g. in tests, are returned as is.
+func (l *StandardLog) withOptions(opts ...zap.Option) zapLogger {
+	if base, ok := l.ZapLogger.(*zap.Logger); ok {
+		return base.WithOptions(opts...)
+	}
+
+	return l.ZapLogger
+}
+
+func (l *StandardLog) getSchema() *schema {
+	if l.schema == nil {
+		return defaultSchema
+	}
+
+	return l.schema
+}
+
+func (l *StandardLog) clone() *StandardLog {
+	child := *l
+
+	return &child
+}
+
+// Close flushes the log, stops async writes and closes the outputs the log owns, i.e. file sinks and exporters.
+// It closes the root logger and all of its children. Entries logged afterwards are discarded,
+// or written to the fallback output if one is set with WithFallbackOutput.
+// Closing an already closed log does nothing.
+func (l *StandardLog) Close() error {
+	if l.lifecycle == nil {
+		l.Flush()
+
+		return nil
+	}
+
+	return l.lifecycle.close()
+}
+
+// Flush writes out buffered entries and syncs the outputs.
+// Errors are passed to the handler set with WithErrorHandler, use Sync to get them instead.
 func (l *StandardLog) Flush() {
-	l.ZapLogger.Sync() //nolint:errcheck,gosec //no error handling here
+	if err := l.Sync(); err != nil && l.lifecycle != nil && l.lifecycle.errorHandler != nil {
+		l.lifecycle.errorHandler(err)
+	}
+}
+
+// Sync writes out buffered entries and syncs the outputs, returning the errors of doing so.
+// Errors of syncing stdout and stderr when they don't support it, e.g. on terminals and pipes, are ignored.
+func (l *StandardLog) Sync() error {
+	return l.ZapLogger.Sync() //nolint:wrapcheck //zap's errors are returned as is
 }
 
 func GetCore(l *StandardLog) zapcore.Core {
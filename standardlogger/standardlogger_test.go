@@ -235,6 +235,12 @@ func (s *MockZapLogger) With(...zap.Field) *zap.Logger {
 	return nil
 }
 
+func (s *MockZapLogger) Log(zapcore.Level, string, ...zap.Field) {
+}
+
+func (s *MockZapLogger) Debug(string, ...zap.Field) {
+}
+
 func (s *MockZapLogger) Info(string, ...zap.Field) {
 }
 
@@ -282,12 +288,8 @@ func TestStandardLog_Close(t *testing.T) {
 	}
 }
 
-func setupLogger() (*standardlogger.StandardLog, *observer.ObservedLogs) {
-	core, logs := observer.New(zap.InfoLevel)
-
-	return &standardlogger.StandardLog{
-		ZapLogger: zap.New(core),
-	}, logs
+func setupLogger() (logger.Log, *observer.ObservedLogs) {
+	return standardlogger.NewForTesting(logger.Labels{})
 }
 
 func TestStandardLog_Infow(t *testing.T) {
@@ -319,6 +321,44 @@ func TestStandardLog_Infow(t *testing.T) {
 	}
 }
 
+func TestStandardLog_Debugw(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := &standardlogger.StandardLog{
+		ZapLogger: zap.New(core),
+	}
+
+	log.Debugw("Debug msg", logger.Fields{"product": "Persistor", "license": "enterprise"})
+
+	expectedMsg := "Debug msg"
+	expectedFields := []zap.Field{zap.String("product", "Persistor"), zap.String("license", "enterprise")}
+
+	entry := logs.All()[0]
+
+	if entry.Message != expectedMsg {
+		t.Errorf("Wrong message, want %s.", expectedMsg)
+	}
+
+	if entry.Level != zap.DebugLevel {
+		t.Errorf("Wrong level, want %s.", zap.DebugLevel)
+	}
+
+	for _, field := range expectedFields {
+		found := false
+
+		for _, v := range entry.Context {
+			if field.Equals(v) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+}
+
 func TestStandardLog_Warnw(t *testing.T) {
 	log, logs := setupLogger()
 	log.Warnw("Warn msg", logger.Fields{"product": "Persistor", "license": "enterprise"})
@@ -858,6 +898,93 @@ func setupLoggerWithLevel(core zapcore.Core) (*standardlogger.StandardLog, *obse
 	}, logs
 }
 
+func TestLogLevelTrace(t *testing.T) {
+	stdoutBck := os.Stdout
+
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Stdout = write
+
+	outC := make(chan string)
+
+	go func() {
+		var buf bytes.Buffer
+
+		io.Copy(&buf, read)
+
+		outC <- buf.String()
+	}()
+
+	labels := logger.Labels{"product": "Persistor"}
+	log := standardlogger.New(labels, standardlogger.WithLogLevel(logger.LevelTrace))
+
+	log.Trace("TRACE")
+	log.Debug("DEBUG")
+	log.Info("INFO")
+
+	write.Close()
+
+	os.Stdout = stdoutBck
+	out := <-outC
+
+	for _, wantSubstring := range []string{
+		"\"level\":\"trace\"",
+		"\"msg\":\"TRACE\"",
+		"\"level\":\"debug\"",
+		"\"msg\":\"DEBUG\"",
+		"\"msg\":\"INFO\"",
+	} {
+		if !strings.Contains(out, wantSubstring) {
+			t.Errorf("Log missing, want substring '%s'.", wantSubstring)
+		}
+	}
+}
+
+func TestLogLevelDebug(t *testing.T) {
+	stderrBck := os.Stderr
+
+	_, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Stderr = write
+
+	defer func() {
+		write.Close()
+
+		os.Stderr = stderrBck
+	}()
+
+	labels := logger.Labels{"product": "Persistor"}
+	log := standardlogger.New(labels, standardlogger.WithLogLevel(logger.LevelDebug))
+	core := standardlogger.GetCore(log.(*standardlogger.StandardLog)) //nolint:forcetypeassert //not necessary in tests.
+
+	logger, logs := setupLoggerWithLevel(core)
+
+	logger.Trace("TRACE")
+	logger.Debug("DEBUG")
+	logger.Info("INFO")
+	logger.Warn("WARN")
+	logger.Error("ERROR", 0)
+
+	expectedMsg := "DEBUG"
+
+	entry := logs.All()[0]
+
+	if entry.Message != expectedMsg {
+		t.Errorf("Wrong message, want %s.", expectedMsg)
+	}
+
+	expectedNumberOfLogs := 4
+	if logs.Len() != expectedNumberOfLogs {
+		t.Errorf("Wrong number of logs, want %d.", expectedNumberOfLogs)
+	}
+}
+
 func TestLogLevelInfo(t *testing.T) {
 	stderrBck := os.Stderr
 
@@ -880,6 +1007,7 @@ func TestLogLevelInfo(t *testing.T) {
 
 	logger, logs := setupLoggerWithLevel(core)
 
+	logger.Debug("DEBUG")
 	logger.Info("INFO")
 	logger.Warn("WARN")
 	logger.Error("ERROR", 0)
@@ -1015,3 +1143,147 @@ func TestLogLevelPanic(t *testing.T) {
 		t.Errorf("Wrong number of logs, want %d.", expectedNumberOfLogs)
 	}
 }
+
+func TestStandardLog_With(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"product": "Persistor"})
+
+	child := log.With(logger.Fields{"reqId": 22})
+	child.Info("child")
+	log.Info("parent")
+
+	childEntry := standardlogger.FindByMessage(logs, "child")[0]
+	for _, field := range []zap.Field{zap.String("product", "Persistor"), zap.Int("reqId", 22)} {
+		if !standardlogger.HasField(childEntry, field) {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+
+	parentEntry := standardlogger.FindByMessage(logs, "parent")[0]
+	if standardlogger.HasField(parentEntry, zap.Int("reqId", 22)) {
+		t.Error("Child field present in parent.")
+	}
+}
+
+func TestStandardLog_WithLabels(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"product": "Persistor"})
+
+	child := log.With(logger.Fields{"reqId": 22}).WithLabels(logger.Labels{"component": "routine"})
+	child.Info("child")
+	log.Info("parent")
+
+	childEntry := standardlogger.FindByMessage(logs, "child")[0]
+	for _, field := range []zap.Field{
+		zap.String("product", "Persistor"),
+		zap.String("component", "routine"),
+		zap.Int("reqId", 22),
+	} {
+		if !standardlogger.HasField(childEntry, field) {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+
+	tagFields := 0
+
+	for _, field := range childEntry.Context {
+		if field.Key == "tags" {
+			tagFields++
+		}
+	}
+
+	if tagFields != 1 {
+		t.Errorf("Wrong number of tags fields %d, want %d.", tagFields, 1)
+	}
+
+	tags, ok := childEntry.ContextMap()["tags"].([]interface{})
+	if !ok || len(tags) != 2 {
+		t.Errorf("Wrong tags %v, want product and component.", tags)
+	}
+
+	parentEntry := standardlogger.FindByMessage(logs, "parent")[0]
+	if standardlogger.HasField(parentEntry, zap.String("component", "routine")) {
+		t.Error("Child label present in parent.")
+	}
+}
+
+func TestStandardLog_WithLabelsWithoutNew(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := &standardlogger.StandardLog{ZapLogger: zap.New(core)}
+
+	log.WithLabels(logger.Labels{"product": "Persistor"}).
+		With(logger.Fields{"reqId": 22}).
+		WithLabels(logger.Labels{"component": "routine"}).
+		Info("child")
+
+	entry := logs.All()[0]
+
+	tagFields := 0
+
+	for _, field := range entry.Context {
+		if field.Key == "tags" {
+			tagFields++
+		}
+	}
+
+	if tagFields != 1 {
+		t.Errorf("Wrong number of tags fields %d, want %d.", tagFields, 1)
+	}
+
+	tags, ok := entry.ContextMap()["tags"].([]interface{})
+	if !ok || len(tags) != 2 || !standardlogger.HasField(entry, zap.Int("reqId", 22)) {
+		t.Errorf("Wrong context %v, want product and component tags and reqId.", entry.ContextMap())
+	}
+}
+
+func TestStandardLog_WithLabelsOverridesParentLabel(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"component": "main"})
+
+	log.WithLabels(logger.Labels{"component": "routine"}).Info("child")
+
+	entry := logs.All()[0]
+	if standardlogger.HasField(entry, zap.String("component", "main")) {
+		t.Error("Parent label not overridden.")
+	}
+
+	if !standardlogger.HasField(entry, zap.String("component", "routine")) {
+		t.Error("Child label missing.")
+	}
+}
+
+func TestStandardLog_WithSharesCore(t *testing.T) {
+	stdoutBck := os.Stdout
+
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Stdout = write
+
+	outC := make(chan string)
+
+	go func() {
+		var buf bytes.Buffer
+
+		io.Copy(&buf, read)
+
+		outC <- buf.String()
+	}()
+
+	log := standardlogger.New(logger.Labels{"label0": "val0"})
+	log.WithLabels(logger.Labels{"label1": "val1"}).Info("Info msg")
+
+	write.Close()
+
+	os.Stdout = stdoutBck
+	out := <-outC
+
+	for _, wantSubstring := range []string{"\"label0\":\"val0\"", "\"label1\":\"val1\"", "\"caller\":\"standardlogger/standardlogger_test.go"} {
+		if !strings.Contains(out, wantSubstring) {
+			t.Errorf("Output missing, want substring '%s'.", wantSubstring)
+		}
+	}
+
+	if strings.Count(out, "\"tags\"") != 1 {
+		t.Errorf("Tags repeated in %s.", out)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+// NewForTesting returns a logger that keeps logged records in memory instead of writing them out.
+// Labels, tags, error codes and options like WithLogLevel are applied the same way as in New.
+// Unlike New, sampling is disabled unless it is enabled with WithSampling, so every logged record is kept.
+func NewForTesting(labels logger.Labels, opts ...Option) (logger.Log, *observer.ObservedLogs) {
+	settings := newSettings(append([]Option{WithoutSampling()}, opts...)...)
+
+	level := zap.NewAtomicLevelAt(getLevelAsZapLevel(settings.logLevel))
+	core, logs := observer.New(level)
+
+	return newStandardLog([]zapcore.Core{core}, labels, level, settings), logs
+}
+
+// FindByMessage returns all logged records with the given message.
+func FindByMessage(logs *observer.ObservedLogs, msg string) []observer.LoggedEntry {
+	return logs.FilterMessage(msg).All()
+}
+
+// HasField reports whether the logged record contains the given field.
+func HasField(entry observer.LoggedEntry, field zap.Field) bool {
+	for _, v := range entry.Context {
+		if field.Equals(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CountByLevel returns the number of logged records with exactly the given level.
+func CountByLevel(logs *observer.ObservedLogs, lvl logger.Level) int {
+	return logs.FilterLevelExact(getLevelAsZapLevel(lvl)).Len()
+}
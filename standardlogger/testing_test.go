@@ -0,0 +1,134 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestNewForTesting(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{})
+	log.Info("Info")
+
+	expectedMsg := "Info"
+
+	entry := logs.All()[0]
+	if entry.Message != expectedMsg {
+		t.Errorf("Wrong message, want %s.", expectedMsg)
+	}
+}
+
+func TestNewForTesting_WithLabelsOnWarn(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"product": "Persistor", "license": "enterprise"})
+	log.Warn("Warn")
+
+	expectedFields := []zap.Field{
+		zap.String("product", "Persistor"),
+		zap.String("license", "enterprise"),
+	}
+
+	entry := logs.All()[0]
+
+	for _, field := range expectedFields {
+		if !standardlogger.HasField(entry, field) {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+}
+
+func TestNewForTesting_TagsPresent(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{"label0": "val0"})
+	log.Info("Info")
+
+	field := zap.Strings("tags", []string{"label0"})
+	if !standardlogger.HasField(logs.All()[0], field) {
+		t.Errorf("Tags not present, want %v.", field)
+	}
+}
+
+func TestNewForTesting_CodePresent(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{})
+	log.Errorw("Error", 1000, logger.F{"objId": 43})
+
+	entries := standardlogger.FindByMessage(logs, "Error")
+	if len(entries) != 1 {
+		t.Fatalf("Wrong number of logs, want %d.", 1)
+	}
+
+	for _, field := range []zap.Field{zap.Uint64("code", 1000), zap.Int("objId", 43)} {
+		if !standardlogger.HasField(entries[0], field) {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+}
+
+func TestNewForTesting_WithLogLevel(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithLogLevel(logger.LevelWarn))
+
+	log.Debug("DEBUG")
+	log.Info("INFO")
+	log.Warn("WARN")
+	log.Warn("WARN")
+	log.Error("ERROR", 0)
+
+	tests := []struct {
+		level    logger.Level
+		expected int
+	}{
+		{logger.LevelDebug, 0},
+		{logger.LevelInfo, 0},
+		{logger.LevelWarn, 2},
+		{logger.LevelError, 1},
+	}
+
+	for _, test := range tests {
+		if count := standardlogger.CountByLevel(logs, test.level); count != test.expected {
+			t.Errorf("CountByLevel(%d)=%d, want %d.", test.level, count, test.expected)
+		}
+	}
+}
+
+func TestFindByMessage(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{})
+
+	log.Info("first")
+	log.Info("second")
+	log.Warn("first")
+
+	if n := len(standardlogger.FindByMessage(logs, "first")); n != 2 {
+		t.Errorf("FindByMessage()=%d entries, want %d.", n, 2)
+	}
+
+	if n := len(standardlogger.FindByMessage(logs, "missing")); n != 0 {
+		t.Errorf("FindByMessage()=%d entries, want %d.", n, 0)
+	}
+}
+
+func TestNewForTesting_KeepsAllRecords(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{})
+
+	for i := 0; i < 150; i++ {
+		log.Info("flood")
+	}
+
+	if logs.Len() != 150 {
+		t.Errorf("Wrong number of logs %d, want 150.", logs.Len())
+	}
+}
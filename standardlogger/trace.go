@@ -0,0 +1,50 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dataphos/lib-logger/logger"
+)
+
+const (
+	traceIDKey    = "trace_id"
+	spanIDKey     = "span_id"
+	traceFlagsKey = "trace_flags"
+)
+
+// WithTraceContext returns Option that makes WithContext add the active OpenTelemetry span context
+// as trace_id, span_id and trace_flags fields.
+func WithTraceContext() Option {
+	return WithContextExtractors(TraceContext)
+}
+
+// TraceContext is ContextExtractor that returns the active OpenTelemetry span context
+// as trace_id, span_id and trace_flags fields, hex encoded as in the W3C traceparent header.
+func TraceContext(ctx context.Context) logger.Fields {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	return logger.Fields{
+		traceIDKey:    spanContext.TraceID().String(),
+		spanIDKey:     spanContext.SpanID().String(),
+		traceFlagsKey: spanContext.TraceFlags().String(),
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Syntio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standardlogger_test
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+
+	"github.com/dataphos/lib-logger/logger"
+	"github.com/dataphos/lib-logger/standardlogger"
+)
+
+func TestWithTraceContext(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "operation")
+	spanContext := span.SpanContext()
+
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithTraceContext())
+	log.WithContext(ctx).Info("traced")
+
+	span.End()
+
+	expectedFields := []zap.Field{
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+		zap.String("trace_flags", "01"),
+	}
+
+	entry := logs.All()[0]
+
+	for _, field := range expectedFields {
+		if !standardlogger.HasField(entry, field) {
+			t.Errorf("Field not found, want %v.", field)
+		}
+	}
+
+	if len(spanContext.TraceID().String()) != 32 || len(spanContext.SpanID().String()) != 16 {
+		t.Errorf("Wrong W3C format of trace_id %s or span_id %s.", spanContext.TraceID(), spanContext.SpanID())
+	}
+
+	if len(exporter.GetSpans()) != 1 {
+		t.Error("Span not exported.")
+	}
+}
+
+func TestWithTraceContext_NoSpan(t *testing.T) {
+	log, logs := standardlogger.NewForTesting(logger.Labels{}, standardlogger.WithTraceContext())
+	log.WithContext(context.Background()).Info("untraced")
+
+	fields := logs.All()[0].ContextMap()
+	for _, key := range []string{"trace_id", "span_id", "trace_flags"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("Field %s present without an active span.", key)
+		}
+	}
+}
@@ -15,16 +15,25 @@
 package standardlogger
 
 import (
+	"sort"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/dataphos/lib-logger/logger"
 )
 
+// traceLevel is a zap level below zapcore.DebugLevel, since zap has no native trace level.
+const traceLevel = zapcore.DebugLevel - 1
+
 func getLevelAsZapLevel(lvl logger.Level) zapcore.Level {
 	var zapLogLevel zapcore.Level
 
 	switch lvl {
+	case logger.LevelTrace:
+		zapLogLevel = traceLevel
+	case logger.LevelDebug:
+		zapLogLevel = zap.DebugLevel
 	case logger.LevelInfo:
 		zapLogLevel = zap.InfoLevel
 	case logger.LevelWarn:
@@ -42,38 +51,70 @@ func getLevelAsZapLevel(lvl logger.Level) zapcore.Level {
 	return zapLogLevel
 }
 
+func getZapLevelAsLevel(lvl zapcore.Level) logger.Level {
+	var logLevel logger.Level
+
+	switch {
+	case lvl <= traceLevel:
+		logLevel = logger.LevelTrace
+	case lvl == zap.DebugLevel:
+		logLevel = logger.LevelDebug
+	case lvl == zap.InfoLevel:
+		logLevel = logger.LevelInfo
+	case lvl == zap.WarnLevel:
+		logLevel = logger.LevelWarn
+	case lvl == zap.ErrorLevel, lvl == zap.DPanicLevel:
+		logLevel = logger.LevelError
+	case lvl == zap.PanicLevel:
+		logLevel = logger.LevelPanic
+	default:
+		logLevel = logger.LevelFatal
+	}
+
+	return logLevel
+}
+
+// GetLabelsAsZapFields converts labels to zap fields, sorted by key.
 func GetLabelsAsZapFields(labels logger.Labels) []zap.Field {
-	fields := make([]zap.Field, len(labels))
-	i := 0
+	fields := make([]zap.Field, 0, len(labels))
 
-	for k, v := range labels {
-		fields[i] = zap.String(k, v)
-		i++
+	for _, key := range labels.Keys() {
+		fields = append(fields, zap.String(key, labels[key]))
 	}
 
 	return fields
 }
 
+// GetLabelsKeys returns the label keys in sorted order, as they are listed in tags.
 func GetLabelsKeys(labels logger.Labels) []string {
-	keys := make([]string, len(labels))
-	i := 0
+	return labels.Keys()
+}
 
-	for k := range labels {
-		keys[i] = k
-		i++
+// GetLoggerFieldsAsZapFields converts fields to zap fields, sorted by key.
+func GetLoggerFieldsAsZapFields(loggerFields logger.Fields) []zap.Field {
+	keys := make([]string, 0, len(loggerFields))
+	for key := range loggerFields {
+		keys = append(keys, key)
 	}
 
-	return keys
-}
+	sort.Strings(keys)
 
-func GetLoggerFieldsAsZapFields(loggerFields logger.Fields) []zap.Field {
-	fields := make([]zap.Field, len(loggerFields))
-	i := 0
+	fields := make([]zap.Field, 0, len(loggerFields))
 
-	for k, v := range loggerFields {
-		fields[i] = zap.Any(k, v)
-		i++
+	for _, key := range keys {
+		fields = append(fields, zap.Any(key, loggerFields[key]))
 	}
 
 	return fields
 }
+
+// levelEncoder serializes traceLevel as "trace" and falls back to zapcore.LowercaseLevelEncoder for zap's own levels.
+func levelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if lvl == traceLevel {
+		enc.AppendString("trace")
+
+		return
+	}
+
+	zapcore.LowercaseLevelEncoder(lvl, enc)
+}